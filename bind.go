@@ -0,0 +1,218 @@
+package query_builder
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrMissingNamedParam = errors.New("missing value for named param")
+	ErrUnusedNamedParam  = errors.New("unused named params")
+)
+
+type namedPosition struct {
+	name string
+	arg  int
+}
+
+type partNamedParam string
+
+// BuildTo writes the bound value when binding. When compiling or rendering
+// the statement, the placeholder gets a sql.NamedArg without value as
+// argument, to be resolved later. Otherwise the build fails: the param has no
+// value.
+func (np partNamedParam) BuildTo(w *Writer) {
+	if w.lookup != nil {
+		v, ok := w.lookup(string(np))
+		if !ok {
+			w.Fail(fmt.Errorf("%w %q", ErrMissingNamedParam, string(np)))
+		}
+		w.WriteParam(v)
+		return
+	}
+	if !w.unresolved {
+		w.Fail(fmt.Errorf("%w %q", ErrMissingNamedParam, string(np)))
+	}
+	w.named = append(w.named, namedPosition{string(np), len(w.args)})
+	w.WriteParam(sql.NamedArg{Name: string(np)})
+}
+
+// NamedParam is a param whose value is given when the statement is bound,
+// so that the same statement can be built for different values. TenantParam
+// is reserved: it fails the build.
+func NamedParam(name string) Part {
+	if name == TenantParam {
+		return Part{partError{fmt.Errorf("%w: %s", ErrReservedParam, name)}}
+	}
+	return Part{partNamedParam(name)}
+}
+
+func bind(b builder, lookup func(name string) (interface{}, bool)) (string, []interface{}, error) {
+	w := Writer{lookup: lookup}
+	b.BuildTo(&w)
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	return w.String(), w.args, nil
+}
+
+func bindMap(b builder, args map[string]interface{}) (string, []interface{}, error) {
+	used := make(map[string]bool, len(args))
+	s, vs, err := bind(b, func(name string) (interface{}, bool) {
+		v, ok := args[name]
+		used[name] = true
+		return v, ok
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(used) != len(args) {
+		var unused []string
+		for k := range args {
+			if !used[k] {
+				unused = append(unused, k)
+			}
+		}
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("%w: %s", ErrUnusedNamedParam, strings.Join(unused, ", "))
+	}
+	return s, vs, nil
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+var structFieldsCache sync.Map
+
+// structFields lists the fields of a struct type by column name: the db tag
+// when present, the field name otherwise. Fields tagged "-" are skipped and
+// embedded structs are flattened, except pointers to unexported types. As
+// with encoding/json, a field hides the fields of the same name deeper in
+// embedded structs, and fields of the same name at the same depth hide each
+// other.
+func structFields(t reflect.Type) []structField {
+	if fs, ok := structFieldsCache.Load(t); ok {
+		return fs.([]structField)
+	}
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	var fs []structField
+	hidden := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	for level := []embedded{{t, nil}}; len(level) != 0; {
+		var next []embedded
+		var found []structField
+		count := map[string]int{}
+		for _, e := range level {
+			if visited[e.t] {
+				continue
+			}
+			for i := 0; i < e.t.NumField(); i++ {
+				f := e.t.Field(i)
+				tag := f.Tag.Get("db")
+				if tag == "-" {
+					continue
+				}
+				index := append(e.index[:len(e.index):len(e.index)], i)
+				if f.Anonymous && tag == "" {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						if !f.IsExported() {
+							// Can't be allocated when scanning.
+							continue
+						}
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{ft, index})
+						continue
+					}
+				}
+				if !f.IsExported() {
+					continue
+				}
+				if tag == "" {
+					tag = f.Name
+				}
+				if !hidden[tag] {
+					count[tag]++
+					found = append(found, structField{tag, index})
+				}
+			}
+		}
+		for _, f := range found {
+			if count[f.name] == 1 {
+				fs = append(fs, f)
+			}
+			hidden[f.name] = true
+		}
+		for _, e := range level {
+			visited[e.t] = true
+		}
+		level = next
+	}
+	sort.Slice(fs, func(i, j int) bool {
+		a, b := fs[i].index, fs[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	structFieldsCache.Store(t, fs)
+	return fs
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, fmt.Errorf("expected a struct, got %T", v)
+	}
+	return rv, nil
+}
+
+func bindStruct(b builder, v interface{}) (string, []interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return "", nil, err
+	}
+	fields := structFields(rv.Type())
+	return bind(b, func(name string) (interface{}, bool) {
+		for _, f := range fields {
+			if f.name == name {
+				fv, err := rv.FieldByIndexErr(f.index)
+				if err != nil {
+					return nil, false
+				}
+				return fv.Interface(), true
+			}
+		}
+		return nil, false
+	})
+}
+
+// Bind builds the query with the values of its named params taken from args.
+// Every named param must have a value and every value must be used.
+func (q *Query) Bind(args map[string]interface{}) (string, []interface{}, error) {
+	return bindMap(q, args)
+}
+
+// BindStruct builds the query with the values of its named params taken from
+// the fields of the struct v, matched by db tag or field name. Fields not
+// used by the query are ignored.
+func (q *Query) BindStruct(v interface{}) (string, []interface{}, error) {
+	return bindStruct(q, v)
+}
@@ -0,0 +1,117 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func namedQuery() *Query {
+	return NewQueryFrom(Table("users")).
+		Select(All()).
+		Where(Field("tenant_id").Eq(NamedParam("tenant_id")), Field("id").Eq(NamedParam("user_id")).Or(Field("parent_id").Eq(NamedParam("user_id")))).
+		Limit(1)
+}
+
+func TestNamedParam(t *testing.T) {
+	s, args, err := buildUnresolved(namedQuery())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND (id = ? OR parent_id = ?) LIMIT 1", s)
+	assert.Equal(t, []interface{}{sql.NamedArg{Name: "tenant_id"}, sql.NamedArg{Name: "user_id"}, sql.NamedArg{Name: "user_id"}}, args)
+	_, _, err = namedQuery().TryBuild()
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	assert.Panics(t, func() { namedQuery().Build() })
+	_, _, err = namedQuery().BuildContext(context.Background())
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND (id = ? OR parent_id = ?) LIMIT 1", fmt.Sprint(namedQuery()))
+	assert.Equal(t, debugMarker+"SELECT * FROM users WHERE tenant_id = :tenant_id AND (id = :user_id OR parent_id = :user_id) LIMIT 1", namedQuery().DebugString())
+}
+
+func TestQuery_Bind(t *testing.T) {
+	q := namedQuery()
+	s, args, err := q.Bind(map[string]interface{}{"tenant_id": 3, "user_id": int64(7)})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND (id = ? OR parent_id = ?) LIMIT 1", s)
+	assert.Equal(t, []interface{}{3, int64(7), int64(7)}, args)
+
+	s, args, err = q.Bind(map[string]interface{}{"tenant_id": 4, "user_id": nil})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{4, nil, nil}, args)
+
+	_, _, err = q.Bind(map[string]interface{}{"tenant_id": 3})
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	assert.EqualError(t, err, `missing value for named param "user_id"`)
+
+	_, _, err = q.Bind(map[string]interface{}{"tenant_id": 3, "user_id": 7, "name": "x", "age": 2})
+	assert.ErrorIs(t, err, ErrUnusedNamedParam)
+	assert.EqualError(t, err, "unused named params: age, name")
+}
+
+type bindBase struct {
+	TenantID int `db:"tenant_id"`
+}
+
+type bindUser struct {
+	bindBase
+	ID       int64  `db:"user_id"`
+	Name     string `db:"-"`
+	Email    string
+	password string
+}
+
+func TestQuery_BindStruct(t *testing.T) {
+	u := bindUser{bindBase: bindBase{3}, ID: 7, Name: "x", Email: "a@b.c"}
+	s, args, err := namedQuery().BindStruct(&u)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND (id = ? OR parent_id = ?) LIMIT 1", s)
+	assert.Equal(t, []interface{}{3, int64(7), int64(7)}, args)
+
+	_, args, err = NewQueryFrom(Table("users")).Select(All()).Where(Field("email").Eq(NamedParam("Email"))).BindStruct(u)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a@b.c"}, args)
+
+	_, _, err = NewQueryFrom(Table("users")).Select(All()).Where(Field("name").Eq(NamedParam("Name"))).BindStruct(u)
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	_, _, err = NewQueryFrom(Table("users")).Select(All()).Where(Field("p").Eq(NamedParam("password"))).BindStruct(u)
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	_, _, err = namedQuery().BindStruct(3)
+	assert.EqualError(t, err, "expected a struct, got int")
+	_, _, err = namedQuery().BindStruct((*bindUser)(nil))
+	assert.EqualError(t, err, "expected a struct, got *query_builder.bindUser")
+}
+
+type shadowBase struct {
+	ID   int `db:"id"`
+	Name string
+}
+
+type shadowOther struct {
+	Name string
+}
+
+type shadowUser struct {
+	shadowBase
+	shadowOther
+	ID int `db:"id"`
+}
+
+type SelfEmbedding struct {
+	*SelfEmbedding
+	ID int `db:"id"`
+}
+
+func TestStructFields(t *testing.T) {
+	u := shadowUser{shadowBase: shadowBase{1, "a"}, shadowOther: shadowOther{"b"}, ID: 2}
+	_, args, err := NewQueryFrom(Table("users")).Select(All()).Where(Field("id").Eq(NamedParam("id"))).BindStruct(u)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{2}, args)
+	_, _, err = NewQueryFrom(Table("users")).Select(All()).Where(Field("name").Eq(NamedParam("Name"))).BindStruct(u)
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+
+	_, args, err = NewQueryFrom(Table("users")).Select(All()).Where(Field("id").Eq(NamedParam("id"))).BindStruct(SelfEmbedding{ID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{3}, args)
+}
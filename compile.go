@@ -0,0 +1,88 @@
+package query_builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CompiledQuery is a query rendered once, for queries run often with
+// different values: only the args of its named params are filled in per run.
+type CompiledQuery struct {
+	sql   string
+	args  []interface{}
+	named []namedPosition
+	names int
+}
+
+// Compile renders the query. Later changes to q don't affect the result.
+func (q *Query) Compile() (*CompiledQuery, error) {
+	w := Writer{unresolved: true}
+	q.BuildTo(&w)
+	if w.err != nil {
+		return nil, w.err
+	}
+	names := make(map[string]bool, len(w.named))
+	for _, v := range w.named {
+		names[v.name] = true
+	}
+	return &CompiledQuery{sql: w.String(), args: w.args, named: w.named, names: len(names)}, nil
+}
+
+func (cq *CompiledQuery) SQL() string {
+	return cq.sql
+}
+
+// Exec returns the args to run the query with, named params taking their
+// values from args. As with Query.Bind, every named param must have a value
+// and every value must be used.
+func (cq *CompiledQuery) Exec(args map[string]interface{}) ([]interface{}, error) {
+	return cq.exec(args, nil)
+}
+
+// ExecContext is Exec taking the tenant of ctx, if any, as the TenantParam
+// param. args can't give it too.
+func (cq *CompiledQuery) ExecContext(ctx context.Context, args map[string]interface{}) ([]interface{}, error) {
+	tenant, _ := TenantFromContext(ctx)
+	return cq.exec(args, tenant)
+}
+
+func (cq *CompiledQuery) exec(args map[string]interface{}, tenant interface{}) ([]interface{}, error) {
+	if _, ok := args[TenantParam]; ok && tenant != nil {
+		return nil, ErrTenantConflict
+	}
+	vs := make([]interface{}, len(cq.args))
+	copy(vs, cq.args)
+	fromCtx := false
+	for _, v := range cq.named {
+		a, ok := args[v.name]
+		if v.name == TenantParam && tenant != nil {
+			a, ok, fromCtx = tenant, true, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrMissingNamedParam, v.name)
+		}
+		vs[v.arg] = a
+	}
+	if n := len(args); fromCtx && n+1 != cq.names || !fromCtx && n != cq.names {
+		var unused []string
+		for k := range args {
+			if !cq.uses(k) {
+				unused = append(unused, k)
+			}
+		}
+		sort.Strings(unused)
+		return nil, fmt.Errorf("%w: %s", ErrUnusedNamedParam, strings.Join(unused, ", "))
+	}
+	return vs, nil
+}
+
+func (cq *CompiledQuery) uses(name string) bool {
+	for _, v := range cq.named {
+		if v.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Compile(t *testing.T) {
+	q := namedQuery().Where(Field("deleted").Eq(ParamBool(false)))
+	cq, err := q.Compile()
+	assert.NoError(t, err)
+	q.Limit(10)
+	assert.Equal(t, "SELECT * FROM users WHERE tenant_id = ? AND (id = ? OR parent_id = ?) AND deleted = ? LIMIT 1", cq.SQL())
+
+	args, err := cq.Exec(map[string]interface{}{"tenant_id": 3, "user_id": 7})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{3, 7, 7, false}, args)
+	args, err = cq.Exec(map[string]interface{}{"tenant_id": 4, "user_id": 8})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{4, 8, 8, false}, args)
+
+	_, err = cq.Exec(map[string]interface{}{"user_id": 8})
+	assert.EqualError(t, err, `missing value for named param "tenant_id"`)
+	_, err = cq.Exec(map[string]interface{}{"tenant_id": 4, "user_id": 8, "x": 1})
+	assert.ErrorIs(t, err, ErrUnusedNamedParam)
+	assert.EqualError(t, err, "unused named params: x")
+}
+
+// BenchmarkQuery_Build is the baseline: the same query built with its value,
+// as it would be without named params.
+func BenchmarkQuery_Build(b *testing.B) {
+	q := wideSelect().Where(Field("t.tenant_id").Eq(ParamInt(3)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Build()
+	}
+}
+
+func BenchmarkQuery_Bind(b *testing.B) {
+	q := wideSelect().Where(Field("t.tenant_id").Eq(NamedParam("tenant_id")))
+	args := map[string]interface{}{"tenant_id": 3}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Bind(args)
+	}
+}
+
+func BenchmarkCompiledQuery_Exec(b *testing.B) {
+	cq, err := wideSelect().Where(Field("t.tenant_id").Eq(NamedParam("tenant_id"))).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	args := map[string]interface{}{"tenant_id": 3}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cq.Exec(args)
+	}
+}
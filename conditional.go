@@ -0,0 +1,80 @@
+package query_builder
+
+import "reflect"
+
+func (q *Query) WhereIf(cond bool, v ...Part) *Query {
+	if cond {
+		q.Where(v...)
+	}
+	return q
+}
+
+func (q *Query) OrderByIf(cond bool, v Part, dir OrderDirection) *Query {
+	if cond {
+		q.OrderBy(v, dir)
+	}
+	return q
+}
+
+func (q *Query) LeftJoinIf(cond bool, table Part, on Part) *Query {
+	if cond {
+		q.LeftJoin(table, on)
+	}
+	return q
+}
+
+func (q *Query) InnerJoinIf(cond bool, table Part, on Part) *Query {
+	if cond {
+		q.InnerJoin(table, on)
+	}
+	return q
+}
+
+// Optional returns f applied to v as a param, or a zero Part, which Where
+// ignores, when v is nil or the zero value of its type. Pointers are
+// dereferenced, so a pointer to a zero value is kept.
+func Optional(v interface{}, f func(Part) Part) Part {
+	if v == nil {
+		return Part{}
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return Part{}
+		}
+		return f(Param(rv.Elem().Interface()))
+	}
+	if rv.IsZero() {
+		return Part{}
+	}
+	return f(Param(v))
+}
+
+// joined reports whether a table referred to as ref is already joined.
+func (q *Query) joined(ref string) bool {
+	for _, v := range q.joinParts {
+		if j, ok := v.builder.(partJoin); ok {
+			if t, ok := j.table.builder.(partTable); ok && t.ref() == ref {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LeftJoinOnce joins table unless a table with the same alias, or name when
+// it has none, is already joined. It lets filters that need the same join
+// each ask for it.
+func (q *Query) LeftJoinOnce(table Part, on Part) *Query {
+	if t, ok := table.builder.(partTable); ok && q.joined(t.ref()) {
+		return q
+	}
+	return q.LeftJoin(table, on)
+}
+
+func (q *Query) InnerJoinOnce(table Part, on Part) *Query {
+	if t, ok := table.builder.(partTable); ok && q.joined(t.ref()) {
+		return q
+	}
+	return q.InnerJoin(table, on)
+}
@@ -0,0 +1,62 @@
+package query_builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type searchParams struct {
+	Name    string
+	MinAge  *int
+	Active  *bool
+	Since   time.Time
+	Tag     string
+	SortAge bool
+}
+
+func search(p searchParams) *Query {
+	q := NewQueryFrom(Table("users").As("u")).Select(Field("u.*"))
+	q.Where(
+		Optional(p.Name, Field("u.name").Eq),
+		Optional(p.MinAge, Field("u.age").Gte),
+		Optional(p.Active, Field("u.active").Eq),
+		Optional(p.Since, Field("u.created_at").Gte),
+	)
+	q.InnerJoinIf(p.Tag != "", Table("user_tags").As("ut"), Field("ut.user_id").Eq(Field("u.id"))).
+		WhereIf(p.Tag != "", Field("ut.tag").Eq(ParamString(p.Tag)))
+	q.OrderByIf(p.SortAge, Field("u.age"), OrderDirectionDesc)
+	return q
+}
+
+func TestQuery_Conditional(t *testing.T) {
+	s, args := search(searchParams{}).Build()
+	assert.Equal(t, "SELECT u.* FROM users AS u", s)
+	assert.Empty(t, args)
+
+	age, active := 18, false
+	s, args = search(searchParams{Name: "bob", MinAge: &age, Active: &active, Tag: "x", SortAge: true}).Build()
+	assert.Equal(t, "SELECT u.* FROM users AS u INNER JOIN user_tags AS ut ON ut.user_id = u.id "+
+		"WHERE u.name = ? AND u.age >= ? AND u.active = ? AND ut.tag = ? ORDER BY u.age DESC", s)
+	assert.Equal(t, []interface{}{"bob", 18, false, "x"}, args)
+	assert.True(t, Optional(nil, Field("a").Eq).IsZero())
+}
+
+func TestQuery_JoinOnce(t *testing.T) {
+	byCountry := func(q *Query) *Query {
+		return q.LeftJoinOnce(Table("addresses").As("a"), Field("a.user_id").Eq(Field("u.id"))).
+			Where(Field("a.country").Eq(ParamString("FR")))
+	}
+	byCity := func(q *Query) *Query {
+		return q.LeftJoinOnce(Table("addresses").As("a"), Field("a.user_id").Eq(Field("u.id"))).
+			Where(Field("a.city").Eq(ParamString("Paris")))
+	}
+	q := NewQueryFrom(Table("users").As("u")).Select(All()).Scopes(byCountry, byCity).
+		InnerJoinOnce(Table("addresses").As("b"), Field("b.user_id").Eq(Field("u.id"))).
+		InnerJoinOnce(Table("orders"), Field("orders.user_id").Eq(Field("u.id"))).
+		InnerJoinOnce(Table("orders"), Field("orders.user_id").Eq(Field("u.id")))
+	assert.Equal(t, "SELECT * FROM users AS u LEFT JOIN addresses AS a ON a.user_id = u.id "+
+		"INNER JOIN addresses AS b ON b.user_id = u.id INNER JOIN orders ON orders.user_id = u.id "+
+		"WHERE a.country = ? AND a.city = ?", builderToString(q))
+}
@@ -0,0 +1,478 @@
+package query_builder
+
+import "strings"
+
+type Column struct {
+	name          string
+	typ           SQLType
+	null          string
+	def           Part
+	autoIncrement bool
+	unique        bool
+	primaryKey    bool
+	comment       string
+}
+
+func NewColumn(name string, t SQLType) *Column {
+	return &Column{name: name, typ: t}
+}
+
+func (c *Column) NotNull() *Column {
+	c.null = " NOT NULL"
+	return c
+}
+
+func (c *Column) Nullable() *Column {
+	c.null = " NULL"
+	return c
+}
+
+func (c *Column) Default(v Part) *Column {
+	c.def = v
+	return c
+}
+
+func (c *Column) AutoIncrement() *Column {
+	c.autoIncrement = true
+	return c
+}
+
+func (c *Column) Unique() *Column {
+	c.unique = true
+	return c
+}
+
+func (c *Column) PrimaryKey() *Column {
+	c.primaryKey = true
+	return c
+}
+
+func (c *Column) Comment(s string) *Column {
+	c.comment = s
+	return c
+}
+
+func (c *Column) BuildTo(w *Writer) {
+	w.WriteString(c.name + " " + c.typ.s + c.null)
+	if !c.def.IsZero() {
+		w.WriteString(" DEFAULT ")
+		c.def.BuildTo(w)
+	}
+	if c.autoIncrement {
+		w.WriteString(" AUTO_INCREMENT")
+	}
+	if c.unique {
+		w.WriteString(" UNIQUE")
+	}
+	if c.primaryKey {
+		w.WriteString(" PRIMARY KEY")
+	}
+	if c.comment != "" {
+		w.WriteString(" COMMENT " + quoteString(c.comment))
+	}
+}
+
+func (c *Column) Build() (string, []interface{}) {
+	return build(c)
+}
+
+type IndexKind int
+
+const (
+	IndexKey IndexKind = iota
+	IndexUnique
+	IndexFullText
+	IndexPrimary
+)
+
+var indexKinds = map[IndexKind]string{
+	IndexKey:      "KEY",
+	IndexUnique:   "UNIQUE KEY",
+	IndexFullText: "FULLTEXT KEY",
+	IndexPrimary:  "PRIMARY KEY",
+}
+
+type Index struct {
+	kind    IndexKind
+	name    string
+	columns []string
+}
+
+func NewIndex(name string, columns ...string) *Index {
+	return &Index{IndexKey, name, columns}
+}
+
+func NewUniqueIndex(name string, columns ...string) *Index {
+	return &Index{IndexUnique, name, columns}
+}
+
+func NewFullTextIndex(name string, columns ...string) *Index {
+	return &Index{IndexFullText, name, columns}
+}
+
+func NewPrimaryKey(columns ...string) *Index {
+	return &Index{IndexPrimary, "", columns}
+}
+
+func (idx *Index) BuildTo(w *Writer) {
+	w.WriteString(indexKinds[idx.kind])
+	if idx.name != "" {
+		w.WriteString(" " + idx.name)
+	}
+	w.WriteString(" (" + strings.Join(idx.columns, ", ") + ")")
+}
+
+func (idx *Index) Build() (string, []interface{}) {
+	return build(idx)
+}
+
+type ReferenceOption string
+
+const (
+	ReferenceRestrict   ReferenceOption = "RESTRICT"
+	ReferenceCascade    ReferenceOption = "CASCADE"
+	ReferenceSetNull    ReferenceOption = "SET NULL"
+	ReferenceNoAction   ReferenceOption = "NO ACTION"
+	ReferenceSetDefault ReferenceOption = "SET DEFAULT"
+)
+
+type ForeignKey struct {
+	name       string
+	columns    []string
+	refTable   string
+	refColumns []string
+	onDelete   ReferenceOption
+	onUpdate   ReferenceOption
+}
+
+func NewForeignKey(name string, columns ...string) *ForeignKey {
+	return &ForeignKey{name: name, columns: columns}
+}
+
+func (fk *ForeignKey) References(table string, columns ...string) *ForeignKey {
+	fk.refTable = table
+	fk.refColumns = columns
+	return fk
+}
+
+func (fk *ForeignKey) OnDelete(o ReferenceOption) *ForeignKey {
+	fk.onDelete = o
+	return fk
+}
+
+func (fk *ForeignKey) OnUpdate(o ReferenceOption) *ForeignKey {
+	fk.onUpdate = o
+	return fk
+}
+
+func (fk *ForeignKey) BuildTo(w *Writer) {
+	if fk.name != "" {
+		w.WriteString("CONSTRAINT " + fk.name + " ")
+	}
+	w.WriteString("FOREIGN KEY (" + strings.Join(fk.columns, ", ") + ") REFERENCES " + fk.refTable + " (" + strings.Join(fk.refColumns, ", ") + ")")
+	if fk.onDelete != "" {
+		w.WriteString(" ON DELETE " + string(fk.onDelete))
+	}
+	if fk.onUpdate != "" {
+		w.WriteString(" ON UPDATE " + string(fk.onUpdate))
+	}
+}
+
+func (fk *ForeignKey) Build() (string, []interface{}) {
+	return build(fk)
+}
+
+type CreateTable struct {
+	name        string
+	temporary   bool
+	ifNotExists bool
+	defs        []builder
+	options     []string
+	as          *Query
+}
+
+func NewCreateTable(name string) *CreateTable {
+	return &CreateTable{name: name}
+}
+
+func (ct *CreateTable) Temporary() *CreateTable {
+	ct.temporary = true
+	return ct
+}
+
+func (ct *CreateTable) IfNotExists() *CreateTable {
+	ct.ifNotExists = true
+	return ct
+}
+
+func (ct *CreateTable) Column(cs ...*Column) *CreateTable {
+	for _, c := range cs {
+		ct.defs = append(ct.defs, c)
+	}
+	return ct
+}
+
+func (ct *CreateTable) Index(idxs ...*Index) *CreateTable {
+	for _, idx := range idxs {
+		ct.defs = append(ct.defs, idx)
+	}
+	return ct
+}
+
+func (ct *CreateTable) PrimaryKey(columns ...string) *CreateTable {
+	return ct.Index(NewPrimaryKey(columns...))
+}
+
+func (ct *CreateTable) ForeignKey(fks ...*ForeignKey) *CreateTable {
+	for _, fk := range fks {
+		ct.defs = append(ct.defs, fk)
+	}
+	return ct
+}
+
+func (ct *CreateTable) Engine(engine string) *CreateTable {
+	ct.options = append(ct.options, "ENGINE="+engine)
+	return ct
+}
+
+func (ct *CreateTable) Charset(charset string) *CreateTable {
+	ct.options = append(ct.options, "DEFAULT CHARSET="+charset)
+	return ct
+}
+
+func (ct *CreateTable) Collate(collation string) *CreateTable {
+	ct.options = append(ct.options, "COLLATE="+collation)
+	return ct
+}
+
+func (ct *CreateTable) Comment(s string) *CreateTable {
+	ct.options = append(ct.options, "COMMENT="+quoteString(s))
+	return ct
+}
+
+// As fills the table from the result of q. Columns may still be declared, to
+// override the types MariaDB would otherwise derive from the select.
+func (ct *CreateTable) As(q *Query) *CreateTable {
+	ct.as = q
+	return ct
+}
+
+func (ct *CreateTable) BuildTo(w *Writer) {
+	w.WriteString("CREATE ")
+	if ct.temporary {
+		w.WriteString("TEMPORARY ")
+	}
+	w.WriteString("TABLE ")
+	if ct.ifNotExists {
+		w.WriteString("IF NOT EXISTS ")
+	}
+	w.WriteString(ct.name)
+	if len(ct.defs) != 0 {
+		w.WriteString(" (")
+		for i, v := range ct.defs {
+			if i != 0 {
+				w.WriteString(", ")
+			}
+			v.BuildTo(w)
+		}
+		w.WriteByte(')')
+	}
+	for _, v := range ct.options {
+		w.WriteString(" " + v)
+	}
+	if ct.as != nil {
+		w.WriteString(" AS ")
+		ct.as.BuildTo(w)
+	}
+}
+
+func (ct *CreateTable) Build() (string, []interface{}) {
+	return build(ct)
+}
+
+type DropTable struct {
+	names     []string
+	temporary bool
+	ifExists  bool
+}
+
+func NewDropTable(names ...string) *DropTable {
+	return &DropTable{names: names}
+}
+
+func (dt *DropTable) Temporary() *DropTable {
+	dt.temporary = true
+	return dt
+}
+
+func (dt *DropTable) IfExists() *DropTable {
+	dt.ifExists = true
+	return dt
+}
+
+func (dt *DropTable) BuildTo(w *Writer) {
+	w.WriteString("DROP ")
+	if dt.temporary {
+		w.WriteString("TEMPORARY ")
+	}
+	w.WriteString("TABLE ")
+	if dt.ifExists {
+		w.WriteString("IF EXISTS ")
+	}
+	w.WriteString(strings.Join(dt.names, ", "))
+}
+
+func (dt *DropTable) Build() (string, []interface{}) {
+	return build(dt)
+}
+
+type AlterTable struct {
+	name  string
+	specs []builder
+}
+
+func NewAlterTable(name string) *AlterTable {
+	return &AlterTable{name: name}
+}
+
+func (at *AlterTable) spec(vs ...builder) *AlterTable {
+	at.specs = append(at.specs, parts(vs))
+	return at
+}
+
+func (at *AlterTable) AddColumn(c *Column) *AlterTable {
+	return at.spec(partString("ADD COLUMN "), c)
+}
+
+func (at *AlterTable) AddColumnFirst(c *Column) *AlterTable {
+	return at.spec(partString("ADD COLUMN "), c, partString(" FIRST"))
+}
+
+func (at *AlterTable) AddColumnAfter(c *Column, after string) *AlterTable {
+	return at.spec(partString("ADD COLUMN "), c, partString(" AFTER "+after))
+}
+
+func (at *AlterTable) DropColumn(name string) *AlterTable {
+	return at.spec(partString("DROP COLUMN " + name))
+}
+
+func (at *AlterTable) ModifyColumn(c *Column) *AlterTable {
+	return at.spec(partString("MODIFY COLUMN "), c)
+}
+
+func (at *AlterTable) ChangeColumn(old string, c *Column) *AlterTable {
+	return at.spec(partString("CHANGE COLUMN "+old+" "), c)
+}
+
+func (at *AlterTable) RenameColumn(old, name string) *AlterTable {
+	return at.spec(partString("RENAME COLUMN " + old + " TO " + name))
+}
+
+func (at *AlterTable) AddIndex(idx *Index) *AlterTable {
+	return at.spec(partString("ADD "), idx)
+}
+
+func (at *AlterTable) DropIndex(name string) *AlterTable {
+	return at.spec(partString("DROP INDEX " + name))
+}
+
+func (at *AlterTable) DropPrimaryKey() *AlterTable {
+	return at.spec(partString("DROP PRIMARY KEY"))
+}
+
+func (at *AlterTable) AddForeignKey(fk *ForeignKey) *AlterTable {
+	return at.spec(partString("ADD "), fk)
+}
+
+func (at *AlterTable) DropForeignKey(name string) *AlterTable {
+	return at.spec(partString("DROP FOREIGN KEY " + name))
+}
+
+func (at *AlterTable) AddCheck(name string, cond Part) *AlterTable {
+	return at.spec(partString("ADD CONSTRAINT "+name+" CHECK ("), cond, partByte(')'))
+}
+
+func (at *AlterTable) DropConstraint(name string) *AlterTable {
+	return at.spec(partString("DROP CONSTRAINT " + name))
+}
+
+func (at *AlterTable) RenameTo(name string) *AlterTable {
+	return at.spec(partString("RENAME TO " + name))
+}
+
+func (at *AlterTable) BuildTo(w *Writer) {
+	w.WriteString("ALTER TABLE " + at.name + " ")
+	for i, v := range at.specs {
+		if i != 0 {
+			w.WriteString(", ")
+		}
+		v.BuildTo(w)
+	}
+}
+
+func (at *AlterTable) Build() (string, []interface{}) {
+	return build(at)
+}
+
+var createIndexKinds = map[IndexKind]string{
+	IndexKey:      "CREATE INDEX ",
+	IndexUnique:   "CREATE UNIQUE INDEX ",
+	IndexFullText: "CREATE FULLTEXT INDEX ",
+}
+
+type CreateIndex struct {
+	table       string
+	index       *Index
+	ifNotExists bool
+}
+
+func NewCreateIndex(table string, idx *Index) *CreateIndex {
+	if idx.kind == IndexPrimary {
+		panic("CREATE INDEX can't create a primary key, use AlterTable.AddIndex")
+	}
+	return &CreateIndex{table: table, index: idx}
+}
+
+func (ci *CreateIndex) IfNotExists() *CreateIndex {
+	ci.ifNotExists = true
+	return ci
+}
+
+func (ci *CreateIndex) BuildTo(w *Writer) {
+	w.WriteString(createIndexKinds[ci.index.kind])
+	if ci.ifNotExists {
+		w.WriteString("IF NOT EXISTS ")
+	}
+	w.WriteString(ci.index.name + " ON " + ci.table + " (" + strings.Join(ci.index.columns, ", ") + ")")
+}
+
+func (ci *CreateIndex) Build() (string, []interface{}) {
+	return build(ci)
+}
+
+type DropIndex struct {
+	table    string
+	name     string
+	ifExists bool
+}
+
+func NewDropIndex(table, name string) *DropIndex {
+	return &DropIndex{table: table, name: name}
+}
+
+func (di *DropIndex) IfExists() *DropIndex {
+	di.ifExists = true
+	return di
+}
+
+func (di *DropIndex) BuildTo(w *Writer) {
+	w.WriteString("DROP INDEX ")
+	if di.ifExists {
+		w.WriteString("IF EXISTS ")
+	}
+	w.WriteString(di.name + " ON " + di.table)
+}
+
+func (di *DropIndex) Build() (string, []interface{}) {
+	return build(di)
+}
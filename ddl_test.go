@@ -0,0 +1,104 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func builderToString(b Statement) string {
+	s, _ := b.Build()
+	return s
+}
+
+func TestColumn(t *testing.T) {
+	assert.Equal(t, `id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY`, builderToString(NewColumn("id", TypeBigInt().Unsigned()).NotNull().AutoIncrement().PrimaryKey()))
+	assert.Equal(t, `email VARCHAR(255) NOT NULL UNIQUE COMMENT 'login'`, builderToString(NewColumn("email", TypeVarchar(255)).NotNull().Unique().Comment("login")))
+	assert.Equal(t, `deleted_at DATETIME NULL DEFAULT NULL`, builderToString(NewColumn("deleted_at", TypeDateTime()).Nullable().Default(Null())))
+	assert.Equal(t, `created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)`, builderToString(NewColumn("created_at", TypeTimestamp(6)).NotNull().Default(Func("CURRENT_TIMESTAMP", ValueInt(6)))))
+}
+
+func TestIndex(t *testing.T) {
+	assert.Equal(t, `KEY idx_name (last_name, first_name)`, builderToString(NewIndex("idx_name", "last_name", "first_name")))
+	assert.Equal(t, `UNIQUE KEY uq_email (email)`, builderToString(NewUniqueIndex("uq_email", "email")))
+	assert.Equal(t, `FULLTEXT KEY ft_body (title, body)`, builderToString(NewFullTextIndex("ft_body", "title", "body")))
+	assert.Equal(t, `PRIMARY KEY (a, b)`, builderToString(NewPrimaryKey("a", "b")))
+	assert.Equal(t, `KEY (a)`, builderToString(NewIndex("", "a")))
+}
+
+func TestForeignKey(t *testing.T) {
+	assert.Equal(t, `CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE RESTRICT`,
+		builderToString(NewForeignKey("fk_user", "user_id").References("users", "id").OnDelete(ReferenceCascade).OnUpdate(ReferenceRestrict)))
+	assert.Equal(t, `FOREIGN KEY (a, b) REFERENCES other (x, y)`, builderToString(NewForeignKey("", "a", "b").References("other", "x", "y")))
+}
+
+func TestCreateTable(t *testing.T) {
+	ct := NewCreateTable("orders").IfNotExists().
+		Column(
+			NewColumn("id", TypeBigInt().Unsigned()).NotNull().AutoIncrement(),
+			NewColumn("user_id", TypeBigInt().Unsigned()).NotNull(),
+			NewColumn("status", TypeEnum("pending", "paid")).NotNull().Default(ValueString("pending")),
+		).
+		PrimaryKey("id").
+		Index(NewIndex("idx_status", "status")).
+		ForeignKey(NewForeignKey("fk_orders_user", "user_id").References("users", "id").OnDelete(ReferenceCascade)).
+		Engine("InnoDB").Charset("utf8mb4").Collate("utf8mb4_unicode_ci").Comment("customer orders")
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS orders (id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, user_id BIGINT UNSIGNED NOT NULL, status ENUM('pending', 'paid') NOT NULL DEFAULT 'pending', PRIMARY KEY (id), KEY idx_status (status), CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='customer orders'`, builderToString(ct))
+}
+
+func TestCreateTable_Temporary(t *testing.T) {
+	ct := NewCreateTable("staging").Temporary().Column(NewColumn("id", TypeInt()))
+	assert.Equal(t, `CREATE TEMPORARY TABLE staging (id INT)`, builderToString(ct))
+}
+
+func TestCreateTable_As(t *testing.T) {
+	q := NewQueryFrom(Table("orders")).Select(Field("id"), Field("total")).Where(Field("status").Eq(ParamString("paid")))
+	s, v := NewCreateTable("paid_orders").Temporary().As(q).Build()
+	assert.Equal(t, `CREATE TEMPORARY TABLE paid_orders AS SELECT id, total FROM orders WHERE status = ?`, s)
+	assert.Equal(t, []interface{}{"paid"}, v)
+	s, _ = NewCreateTable("paid_orders").Column(NewColumn("total", TypeDecimal(12, 2))).As(q).Build()
+	assert.Equal(t, `CREATE TABLE paid_orders (total DECIMAL(12,2)) AS SELECT id, total FROM orders WHERE status = ?`, s)
+}
+
+func TestDropTable(t *testing.T) {
+	assert.Equal(t, `DROP TABLE a`, builderToString(NewDropTable("a")))
+	assert.Equal(t, `DROP TEMPORARY TABLE IF EXISTS a, b`, builderToString(NewDropTable("a", "b").Temporary().IfExists()))
+}
+
+func TestAlterTable(t *testing.T) {
+	at := NewAlterTable("users").
+		AddColumn(NewColumn("nickname", TypeVarchar(64)).Nullable()).
+		AddColumnAfter(NewColumn("middle_name", TypeVarchar(64)), "first_name").
+		AddColumnFirst(NewColumn("uuid", TypeBinary(16)).NotNull()).
+		DropColumn("legacy").
+		ModifyColumn(NewColumn("email", TypeVarchar(320)).NotNull()).
+		ChangeColumn("name", NewColumn("full_name", TypeVarchar(255)).NotNull()).
+		RenameColumn("pwd", "password_hash")
+	assert.Equal(t, `ALTER TABLE users ADD COLUMN nickname VARCHAR(64) NULL, ADD COLUMN middle_name VARCHAR(64) AFTER first_name, ADD COLUMN uuid BINARY(16) NOT NULL FIRST, DROP COLUMN legacy, MODIFY COLUMN email VARCHAR(320) NOT NULL, CHANGE COLUMN name full_name VARCHAR(255) NOT NULL, RENAME COLUMN pwd TO password_hash`, builderToString(at))
+}
+
+func TestAlterTable_Indexes(t *testing.T) {
+	at := NewAlterTable("orders").
+		DropPrimaryKey().
+		AddIndex(NewPrimaryKey("id", "created_at")).
+		AddIndex(NewUniqueIndex("uq_ref", "reference")).
+		DropIndex("idx_old").
+		AddForeignKey(NewForeignKey("fk_user", "user_id").References("users", "id").OnDelete(ReferenceSetNull)).
+		DropForeignKey("fk_legacy").
+		AddCheck("chk_total", Field("total").Gte(ValueInt(0))).
+		DropConstraint("chk_old").
+		RenameTo("customer_orders")
+	assert.Equal(t, `ALTER TABLE orders DROP PRIMARY KEY, ADD PRIMARY KEY (id, created_at), ADD UNIQUE KEY uq_ref (reference), DROP INDEX idx_old, ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL, DROP FOREIGN KEY fk_legacy, ADD CONSTRAINT chk_total CHECK (total >= 0), DROP CONSTRAINT chk_old, RENAME TO customer_orders`, builderToString(at))
+}
+
+func TestCreateIndex(t *testing.T) {
+	assert.Equal(t, `CREATE INDEX idx_status ON orders (status, created_at)`, builderToString(NewCreateIndex("orders", NewIndex("idx_status", "status", "created_at"))))
+	assert.Equal(t, `CREATE UNIQUE INDEX IF NOT EXISTS uq_ref ON orders (reference)`, builderToString(NewCreateIndex("orders", NewUniqueIndex("uq_ref", "reference")).IfNotExists()))
+	assert.Equal(t, `CREATE FULLTEXT INDEX ft_body ON posts (title, body)`, builderToString(NewCreateIndex("posts", NewFullTextIndex("ft_body", "title", "body"))))
+	assert.Panics(t, func() { NewCreateIndex("orders", NewPrimaryKey("id")) })
+}
+
+func TestDropIndex(t *testing.T) {
+	assert.Equal(t, `DROP INDEX idx_status ON orders`, builderToString(NewDropIndex("orders", "idx_status")))
+	assert.Equal(t, `DROP INDEX IF EXISTS idx_status ON orders`, builderToString(NewDropIndex("orders", "idx_status").IfExists()))
+}
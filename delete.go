@@ -0,0 +1,74 @@
+package query_builder
+
+import "strconv"
+
+type DeleteQuery struct {
+	table        Part
+	whereParts   []Part
+	orderByParts []Part
+	limit        int
+	returning    []Part
+}
+
+func NewDelete(table Part) *DeleteQuery {
+	return &DeleteQuery{table: table}
+}
+
+// Where adds conditions joined with AND. Zero parts are ignored.
+func (dq *DeleteQuery) Where(v ...Part) *DeleteQuery {
+	for _, p := range v {
+		if !p.IsZero() {
+			dq.whereParts = append(dq.whereParts, p)
+		}
+	}
+	return dq
+}
+
+func (dq *DeleteQuery) OrderBy(v Part, dir OrderDirection) *DeleteQuery {
+	switch dir {
+	case OrderDirectionAsc:
+		dq.orderByParts = append(dq.orderByParts, Part{parts{v, partString(" ASC")}})
+	case OrderDirectionDesc:
+		dq.orderByParts = append(dq.orderByParts, Part{parts{v, partString(" DESC")}})
+	}
+	return dq
+}
+
+func (dq *DeleteQuery) Limit(limit int) *DeleteQuery {
+	dq.limit = limit
+	return dq
+}
+
+// Returning makes the statement return v for each deleted row, MariaDB 10.0
+// and later.
+func (dq *DeleteQuery) Returning(v ...Part) *DeleteQuery {
+	dq.returning = append(dq.returning, v...)
+	return dq
+}
+
+func (dq *DeleteQuery) BuildTo(w *Writer) {
+	w.WriteString("DELETE FROM ")
+	writeTableRef(w, dq.table)
+	where := dq.whereParts
+	if c := tenantFilter(w, dq.table); !c.IsZero() {
+		where = append(where[:len(where):len(where)], c)
+	}
+	writeWhere(w, where)
+	if len(dq.orderByParts) != 0 {
+		w.WriteString(" ORDER BY ")
+		writeList(w, dq.orderByParts, ", ")
+	}
+	if dq.limit != 0 {
+		w.WriteString(" LIMIT ")
+		w.buf = strconv.AppendInt(w.buf, int64(dq.limit), 10)
+	}
+	writeReturning(w, dq.returning)
+}
+
+func (dq *DeleteQuery) Build() (string, []interface{}) {
+	return build(dq)
+}
+
+func (dq *DeleteQuery) TryBuild() (string, []interface{}, error) {
+	return tryBuild(dq)
+}
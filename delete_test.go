@@ -0,0 +1,19 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteQuery(t *testing.T) {
+	s, args := NewDelete(Table("t")).
+		Where(Field("a").Eq(ParamInt(1)).Or(Field("b").Eq(ParamInt(2))), Field("c").InValues([]int{}, EmptyListSkip)).
+		OrderBy(Field("id"), OrderDirectionDesc).
+		Limit(10).
+		Returning(Field("id")).
+		Build()
+	assert.Equal(t, "DELETE FROM t WHERE (a = ? OR b = ?) ORDER BY id DESC LIMIT 10 RETURNING id", s)
+	assert.Equal(t, []interface{}{1, 2}, args)
+	assert.Equal(t, "DELETE FROM t", builderToString(NewDelete(Table("t"))))
+}
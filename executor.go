@@ -0,0 +1,185 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Statement is anything that builds to SQL and its params: queries, parts and
+// the DDL builders.
+type Statement interface {
+	Build() (string, []interface{})
+}
+
+// Conn is what an Executor runs statements on. *sql.DB, *sql.Tx, *sql.Conn
+// and *StmtCache all satisfy it.
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Executor builds statements and runs them on a Conn, calling its hooks
+// around each of them.
+type Executor struct {
+	conn  Conn
+	hooks []Hook
+}
+
+func NewExecutor(conn Conn, hooks ...Hook) *Executor {
+	return &Executor{conn: conn, hooks: hooks}
+}
+
+type tryBuilder interface {
+	TryBuild() (string, []interface{}, error)
+}
+
+type contextBuilder interface {
+	BuildContext(ctx context.Context) (string, []interface{}, error)
+}
+
+// before builds stmt, with the tenant of ctx when it supports it. If the
+// build fails, the statement isn't run: the execute hooks see an event with
+// no SQL and the build error as Err.
+func (e *Executor) before(ctx context.Context, stmt Statement) (context.Context, *Event, error) {
+	for _, h := range e.hooks {
+		ctx = h.BeforeBuild(ctx, stmt)
+	}
+	var s string
+	var args []interface{}
+	var err error
+	switch b := stmt.(type) {
+	case contextBuilder:
+		s, args, err = b.BuildContext(ctx)
+	case tryBuilder:
+		s, args, err = b.TryBuild()
+	default:
+		s, args = stmt.Build()
+	}
+	ev := &Event{SQL: s, Args: args, Rows: -1, Start: time.Now()}
+	for _, h := range e.hooks {
+		ctx = h.BeforeExecute(ctx, ev)
+	}
+	if err != nil {
+		ev.Err = err
+		e.after(ctx, ev)
+		return ctx, nil, err
+	}
+	return ctx, ev, nil
+}
+
+func (e *Executor) after(ctx context.Context, ev *Event) {
+	ev.Duration = time.Since(ev.Start)
+	for i := len(e.hooks) - 1; i >= 0; i-- {
+		e.hooks[i].AfterExecute(ctx, ev)
+	}
+}
+
+func (e *Executor) ExecContext(ctx context.Context, stmt Statement) (sql.Result, error) {
+	ctx, ev, err := e.before(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.conn.ExecContext(ctx, ev.SQL, ev.Args...)
+	if err == nil {
+		ev.Rows, ev.Err = res.RowsAffected()
+	} else {
+		ev.Err = err
+	}
+	e.after(ctx, ev)
+	return res, err
+}
+
+// QueryContext runs stmt. The hooks see the query end when the rows are
+// closed, with the number of rows read.
+func (e *Executor) QueryContext(ctx context.Context, stmt Statement) (*Rows, error) {
+	ctx, ev, err := e.before(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := e.conn.QueryContext(ctx, ev.SQL, ev.Args...)
+	if err != nil {
+		ev.Err = err
+		e.after(ctx, ev)
+		return nil, err
+	}
+	ev.Rows = 0
+	return &Rows{Rows: rows, ctx: ctx, ev: ev, e: e}, nil
+}
+
+func (e *Executor) QueryRowContext(ctx context.Context, stmt Statement) *Row {
+	rows, err := e.QueryContext(ctx, stmt)
+	return &Row{rows: rows, err: err}
+}
+
+// Rows wraps *sql.Rows to report the end of the query to the hooks.
+type Rows struct {
+	*sql.Rows
+	ctx  context.Context
+	ev   *Event
+	e    *Executor
+	done bool
+}
+
+func (r *Rows) Next() bool {
+	if r.Rows.Next() {
+		r.ev.Rows++
+		return true
+	}
+	r.finish()
+	return false
+}
+
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	r.finish()
+	return err
+}
+
+func (r *Rows) finish() {
+	if r.done {
+		return
+	}
+	r.done = true
+	if r.e != nil {
+		r.ev.Err = r.Rows.Err()
+		r.e.after(r.ctx, r.ev)
+	}
+}
+
+// Row is the result of QueryRowContext, of an Executor or a StmtCache,
+// working as *sql.Row does.
+type Row struct {
+	rows *Rows
+	err  error
+}
+
+func (r *Row) scan(f func() error) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := f(); err != nil {
+		return err
+	}
+	return r.rows.Close()
+}
+
+func (r *Row) Scan(dest ...interface{}) error {
+	return r.scan(func() error { return r.rows.Scan(dest...) })
+}
+
+// ScanStruct scans the row into the struct dest points to, see ScanStruct.
+func (r *Row) ScanStruct(dest interface{}) error {
+	return r.scan(func() error { return ScanStruct(r.rows, dest) })
+}
+
+func (r *Row) Err() error {
+	return r.err
+}
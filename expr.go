@@ -0,0 +1,85 @@
+package query_builder
+
+// precedence follows the MariaDB operator precedence table, from the loosest
+// binding operator to the tightest.
+type precedence int
+
+const (
+	precOr precedence = iota + 1
+	precXor
+	precAnd
+	precNot
+	precBetween
+	precCompare
+	precBitOr
+	precBitAnd
+	precShift
+	precAdd
+	precMul
+	precBitXor
+	precUnary
+	precAtom
+)
+
+// partExpr is an operator expression. It remembers its operator and precedence
+// so that it only gets parenthesized when used as the operand of an operator
+// binding tighter than itself.
+type partExpr struct {
+	op   string
+	prec precedence
+	parts
+}
+
+func precedenceOf(b builder) precedence {
+	switch t := b.(type) {
+	case partExpr:
+		return t.prec
+	case Part:
+		return precedenceOf(t.builder)
+	}
+	return precAtom
+}
+
+func operatorOf(b builder) string {
+	switch t := b.(type) {
+	case partExpr:
+		return t.op
+	case Part:
+		return operatorOf(t.builder)
+	}
+	return ""
+}
+
+var associativeOperators = map[string]bool{
+	"OR":  true,
+	"XOR": true,
+	"AND": true,
+	"|":   true,
+	"&":   true,
+	"+":   true,
+	"*":   true,
+	"^":   true,
+}
+
+func operand(v Part, paren bool) builder {
+	if paren {
+		return Cond(v)
+	}
+	return v
+}
+
+// binary renders l op r. Operators are left associative: the right operand is
+// also parenthesized at equal precedence, unless it is the same associative
+// operator.
+func binary(l Part, op string, prec precedence, r Part) partExpr {
+	rp := precedenceOf(r)
+	return partExpr{op, prec, parts{
+		operand(l, precedenceOf(l) < prec),
+		partString(" " + op + " "),
+		operand(r, rp < prec || rp == prec && !(associativeOperators[op] && operatorOf(r) == op)),
+	}}
+}
+
+func unary(op string, prec precedence, v Part) partExpr {
+	return partExpr{op, prec, parts{partString(op), operand(v, precedenceOf(v) < prec || operatorOf(v) == op)}}
+}
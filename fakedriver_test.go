@@ -0,0 +1,163 @@
+package query_builder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+type fakeCall struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB is a database/sql driver recording what goes through it. Queries are
+// answered by rows when set, by a single row holding 1 otherwise.
+type fakeDB struct {
+	mu        sync.Mutex
+	prepares  []string
+	closes    []string
+	execs     []fakeCall
+	queries   []fakeCall
+	begins    int
+	commits   int
+	rollbacks int
+	rows      func(query string, args []driver.Value) ([]string, [][]driver.Value, error)
+	execErr   func(query string, args []driver.Value) error
+	commitErr error
+}
+
+var fakeDBs sync.Map
+
+func init() {
+	sql.Register("query_builder_fake", fakeDriver{})
+}
+
+func openFakeDB(t testing.TB) (*sql.DB, *fakeDB) {
+	f := &fakeDB{}
+	fakeDBs.Store(t.Name(), f)
+	db, err := sql.Open("query_builder_fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	f, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", name)
+	}
+	return &fakeConn{f.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.prepares = append(c.db.prepares, query)
+	return &fakeStmt{c.db, query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.begins++
+	return fakeTx{c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.commits++
+	return tx.db.commitErr
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.closes = append(s.db.closes, s.query)
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if s.db.execErr != nil {
+		if err := s.db.execErr(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	s.db.execs = append(s.db.execs, fakeCall{s.query, args})
+	return driver.RowsAffected(int64(len(s.db.execs))), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.queries = append(s.db.queries, fakeCall{s.query, args})
+	if s.db.rows == nil {
+		return &fakeRows{[]string{"v"}, [][]driver.Value{{int64(1)}}}, nil
+	}
+	columns, values, err := s.db.rows(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns, values}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
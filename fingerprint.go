@@ -0,0 +1,99 @@
+package query_builder
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Normalize reduces SQL to its shape: literals become placeholders, IN lists
+// of placeholders collapse to IN (...), comments are dropped and whitespace
+// is squeezed.
+func Normalize(s string) string {
+	var ts []token
+	for _, t := range tokenize(s) {
+		switch t.kind {
+		case tokenComment:
+			continue
+		case tokenSpace:
+			if len(ts) == 0 || ts[len(ts)-1].kind == tokenSpace {
+				continue
+			}
+			t.s = " "
+		case tokenString, tokenNumber:
+			t = token{tokenParam, "?"}
+		}
+		ts = append(ts, t)
+	}
+	if len(ts) != 0 && ts[len(ts)-1].kind == tokenSpace {
+		ts = ts[:len(ts)-1]
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	prev := ""
+	for i := 0; i < len(ts); i++ {
+		t := ts[i]
+		if t.s == "(" && prev == "IN" {
+			if end := paramListEnd(ts, i+1); end > 0 {
+				sb.WriteString("(...)")
+				i = end
+				continue
+			}
+		}
+		if t.kind == tokenWord {
+			prev = strings.ToUpper(t.s)
+		} else if t.kind != tokenSpace {
+			prev = t.s
+		}
+		sb.WriteString(t.s)
+	}
+	return sb.String()
+}
+
+// paramListEnd returns the index of the parenthesis closing a list made of
+// placeholders only, starting at i, or -1.
+func paramListEnd(ts []token, i int) int {
+	expectParam := true
+	for ; i < len(ts); i++ {
+		switch t := ts[i]; {
+		case t.kind == tokenSpace:
+		case expectParam && t.kind == tokenParam:
+			expectParam = false
+		case !expectParam && t.s == ",":
+			expectParam = true
+		case !expectParam && t.s == ")":
+			return i
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// FingerprintSQL returns a stable hash of the normalized s, identifying
+// queries of the same shape whatever their values.
+func FingerprintSQL(s string) string {
+	h := fnv.New64a()
+	h.Write([]byte(Normalize(s)))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Fingerprint doesn't depend on the tenant: tenant conditions are rendered
+// with the TenantParam param. It panics as Build does when the build fails;
+// TryFingerprint returns the error instead.
+func (q *Query) Fingerprint() string {
+	fp, err := q.TryFingerprint()
+	if err != nil {
+		panic(err)
+	}
+	return fp
+}
+
+func (q *Query) TryFingerprint() (string, error) {
+	s, _, err := buildUnresolved(q)
+	if err != nil {
+		return "", err
+	}
+	return FingerprintSQL(s), nil
+}
@@ -0,0 +1,31 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, `SELECT * FROM t WHERE a IN (...) AND b = ? AND c = ? LIMIT ?`, Normalize("SELECT * FROM t WHERE a IN (?, ?, ?) AND b = 'x' AND c = 12 LIMIT 10"))
+	assert.Equal(t, `SELECT * FROM t WHERE a NOT IN (...)`, Normalize("SELECT * FROM t WHERE a NOT IN (1,2)"))
+	assert.Equal(t, `SELECT * FROM t WHERE a IN (NULL)`, Normalize("SELECT * FROM t WHERE a IN (NULL)"))
+	assert.Equal(t, `SELECT * FROM t WHERE a IN (SELECT b FROM u WHERE c = ?)`, Normalize("SELECT * FROM t WHERE a IN (SELECT b FROM u WHERE c = 'it''s')"))
+	assert.Equal(t, `SELECT CONCAT(?, ?) FROM t`, Normalize("  SELECT /* hint */ CONCAT('a',  ?)\n FROM t  "))
+	assert.Equal(t, `SELECT t1.a FROM t1`, Normalize("SELECT t1.a FROM t1"))
+}
+
+func TestQuery_Fingerprint(t *testing.T) {
+	q1 := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").In(ParamInts([]int{1, 2, 3})), Field("b").Eq(ValueString("x")))
+	q2 := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").In(ParamInts([]int{4, 5})), Field("b").Eq(ValueString("y")))
+	q3 := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").In(ParamInts([]int{4, 5})), Field("c").Eq(ValueString("y")))
+	assert.Equal(t, q1.Fingerprint(), q2.Fingerprint())
+	assert.NotEqual(t, q1.Fingerprint(), q3.Fingerprint())
+	assert.Len(t, q1.Fingerprint(), 16)
+	assert.Equal(t, FingerprintSQL("SELECT * FROM t WHERE a IN (...) AND b = ?"), q1.Fingerprint())
+
+	q4 := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").InValues([]int{}, EmptyListError))
+	_, err := q4.TryFingerprint()
+	assert.ErrorIs(t, err, ErrEmptyList)
+	assert.Panics(t, func() { q4.Fingerprint() })
+}
@@ -0,0 +1,177 @@
+package query_builder
+
+import "strings"
+
+// Formatter lays built SQL out over several lines: one clause per line,
+// subqueries, CTEs and CASE branches indented. Only whitespace between
+// tokens changes, so the parameters still line up with the placeholders.
+type Formatter struct {
+	Indent            string
+	UppercaseKeywords bool
+}
+
+var DefaultFormatter = Formatter{Indent: "  "}
+
+var clauseKeywords = map[string]bool{
+	"SELECT":    true,
+	"FROM":      true,
+	"WHERE":     true,
+	"GROUP":     true,
+	"HAVING":    true,
+	"WINDOW":    true,
+	"ORDER":     true,
+	"LIMIT":     true,
+	"UNION":     true,
+	"INTERSECT": true,
+	"EXCEPT":    true,
+	"VALUES":    true,
+	"SET":       true,
+	"RETURNING": true,
+	"WITH":      true,
+}
+
+var joinKeywords = map[string]bool{
+	"JOIN":          true,
+	"LEFT":          true,
+	"RIGHT":         true,
+	"INNER":         true,
+	"CROSS":         true,
+	"NATURAL":       true,
+	"OUTER":         true,
+	"STRAIGHT_JOIN": true,
+}
+
+var keywords = map[string]bool{}
+
+func init() {
+	for _, v := range strings.Fields(`ALL AND AGAINST AS ASC BETWEEN BOOLEAN BY CASE DELETE DESC DISTINCT
+		DIV DUPLICATE ELSE END ESCAPE EXISTS EXPANSION FALSE FOR IGNORE IN INSERT INTERVAL INTO IS KEY LANGUAGE
+		LIKE MATCH MOD MODE NOT NULL ON OR OVER PARTITION QUERY RECURSIVE REGEXP REPLACE RLIKE THEN TRUE
+		UPDATE USING WHEN WITH XOR`) {
+		keywords[v] = true
+	}
+	for k := range clauseKeywords {
+		keywords[k] = true
+	}
+	for k := range joinKeywords {
+		keywords[k] = true
+	}
+}
+
+type formatFrameKind int
+
+const (
+	formatStatement formatFrameKind = iota
+	formatParen
+	formatCase
+)
+
+type formatFrame struct {
+	kind    formatFrameKind
+	indent  int
+	clause  string
+	between bool
+}
+
+func nextSignificant(ts []token, i int) token {
+	for ; i < len(ts); i++ {
+		if ts[i].kind != tokenSpace && ts[i].kind != tokenComment {
+			return ts[i]
+		}
+	}
+	return token{}
+}
+
+func (f Formatter) Format(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + len(s)>>2)
+	frames := []formatFrame{{kind: formatStatement}}
+	lineIndent, breakAt := 0, -1
+	space, prev := "", ""
+	ts := tokenize(s)
+	for i, t := range ts {
+		if t.kind == tokenSpace {
+			space = t.s
+			continue
+		}
+		top := &frames[len(frames)-1]
+		word := ""
+		if t.kind == tokenWord {
+			word = strings.ToUpper(t.s)
+		}
+		switch {
+		case t.s == "(":
+			next := strings.ToUpper(nextSignificant(ts, i+1).s)
+			if next == "SELECT" || next == "WITH" {
+				frames = append(frames, formatFrame{kind: formatStatement, indent: lineIndent + 1})
+			} else {
+				frames = append(frames, formatFrame{kind: formatParen, indent: lineIndent})
+			}
+		case t.s == ")":
+			for len(frames) > 1 {
+				closed := frames[len(frames)-1]
+				frames = frames[:len(frames)-1]
+				if closed.kind == formatStatement {
+					breakAt = closed.indent - 1
+				}
+				if closed.kind != formatCase {
+					break
+				}
+			}
+		case word == "CASE":
+			frames = append(frames, formatFrame{kind: formatCase, indent: lineIndent})
+		case top.kind == formatCase && (word == "WHEN" || word == "ELSE"):
+			breakAt = top.indent + 1
+		case top.kind == formatCase && word == "END":
+			breakAt = top.indent
+			frames = frames[:len(frames)-1]
+		case top.kind != formatStatement:
+		case clauseKeywords[word],
+			joinKeywords[word] && !joinKeywords[prev] && nextSignificant(ts, i+1).s != "(":
+			breakAt = top.indent
+			top.clause = word
+		case word == "BETWEEN":
+			top.between = true
+		case word == "AND" && top.between:
+			top.between = false
+		case (word == "AND" || word == "OR") && (top.clause == "WHERE" || top.clause == "HAVING" || joinKeywords[top.clause]):
+			breakAt = top.indent + 1
+		}
+
+		if breakAt >= 0 && sb.Len() != 0 {
+			sb.WriteByte('\n')
+			sb.WriteString(strings.Repeat(f.Indent, breakAt))
+			lineIndent = breakAt
+		} else if sb.Len() != 0 {
+			sb.WriteString(space)
+		}
+		breakAt, space = -1, ""
+		if word != "" {
+			prev = word
+		}
+		if f.UppercaseKeywords && keywords[word] {
+			sb.WriteString(word)
+		} else {
+			sb.WriteString(t.s)
+		}
+
+		switch {
+		case t.s == "(" && frames[len(frames)-1].kind == formatStatement:
+			breakAt = frames[len(frames)-1].indent
+		case t.s == "," && top.kind == formatStatement && top.clause == "WITH":
+			breakAt = top.indent
+		}
+	}
+	return sb.String()
+}
+
+// BuildPretty renders tenant conditions with the TenantParam param, as a
+// tenant is not needed for reading the query. It panics as Build does on
+// other errors.
+func (q *Query) BuildPretty() (string, []interface{}) {
+	s, ps, err := buildUnresolved(q)
+	if err != nil {
+		panic(err)
+	}
+	return DefaultFormatter.Format(s), ps
+}
@@ -0,0 +1,49 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_BuildPretty(t *testing.T) {
+	q := NewQueryFrom(Table("users").As("u")).
+		Select(Field("u.id"), NewCase().When(Field("c.n").Gt(ValueInt(10)), ValueString("gold")).Else(ValueString("none")).Part().As("tier")).
+		LeftJoin(Table("counts").As("c"), Field("c.user_id").Eq(Field("u.id")).And(Field("c.n").Gt(ParamInt(0)))).
+		Where(
+			Field("u.id").In(Params([]interface{}{1, 2})),
+			Field("u.age").Between(ValueInt(18), ValueInt(65)),
+			Exists(NewQueryFrom(Table("bans").As("b")).Select(All()).Where(Field("b.user_id").Eq(Field("u.id")))),
+		).
+		OrderBy(Field("u.id"), OrderDirectionDesc).
+		Limit(10)
+	s, v := q.BuildPretty()
+	assert.Equal(t, `SELECT u.id, CASE
+  WHEN c.n > 10 THEN 'gold'
+  ELSE 'none'
+END AS tier
+FROM users AS u
+LEFT JOIN counts AS c ON c.user_id = u.id
+  AND c.n > ?
+WHERE u.id IN (?, ?)
+  AND u.age BETWEEN 18 AND 65
+  AND EXISTS(
+    SELECT *
+    FROM bans AS b
+    WHERE b.user_id = u.id
+  )
+ORDER BY u.id DESC
+LIMIT 10`, s)
+	_, bv := q.Build()
+	assert.Equal(t, bv, v)
+}
+
+func TestFormatter_Format(t *testing.T) {
+	f := Formatter{Indent: "\t", UppercaseKeywords: true}
+	assert.Equal(t, "WITH a AS (\n\tSELECT 1\n),\nb AS (\n\tSELECT 2\n)\nSELECT *\nFROM a\nINNER JOIN b ON TRUE",
+		f.Format("with a as (select 1), b as (select 2) select * from a inner join b on true"))
+	assert.Equal(t, "SELECT LEFT(name, 2)\nFROM t\nLEFT OUTER JOIN x ON x.id = t.id\nWHERE a = 'select from where'\n\tOR b IN (\n\t\tSELECT 1\n\t\tFROM d\n\t)",
+		f.Format("select left(name, 2) from t left outer join x on x.id = t.id where a = 'select from where' or b in (select 1 from d)"))
+	assert.Equal(t, "(\n  SELECT a\n  FROM t\n)\nUNION (\n  SELECT a\n  FROM u\n)",
+		DefaultFormatter.Format(partToString(Union(NewQueryFrom(Table("t")).Select(Field("a")), NewQueryFrom(Table("u")).Select(Field("a"))))))
+}
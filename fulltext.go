@@ -0,0 +1,45 @@
+package query_builder
+
+import "strings"
+
+type SearchMode int
+
+const (
+	SearchNaturalLanguage SearchMode = iota
+	SearchNaturalLanguageWithQueryExpansion
+	SearchBoolean
+	SearchWithQueryExpansion
+)
+
+var searchModes = map[SearchMode]string{
+	SearchNaturalLanguage:                   " IN NATURAL LANGUAGE MODE",
+	SearchNaturalLanguageWithQueryExpansion: " IN NATURAL LANGUAGE MODE WITH QUERY EXPANSION",
+	SearchBoolean:                           " IN BOOLEAN MODE",
+	SearchWithQueryExpansion:                " WITH QUERY EXPANSION",
+}
+
+type FullTextMatch struct {
+	columns []Part
+}
+
+func Match(columns ...Part) FullTextMatch {
+	return FullTextMatch{columns}
+}
+
+// Against renders the MATCH ... AGAINST expression. It yields the relevance
+// score, so it can be used as a predicate as well as selected or ordered by.
+func (m FullTextMatch) Against(v Part, mode SearchMode) Part {
+	return Part{parts{partString("MATCH ("), List(m.columns...), partString(") AGAINST ("), v, partString(searchModes[mode]), partByte(')')}}
+}
+
+// EscapeBooleanSearch strips the boolean mode operators from s, so that user
+// input is searched for as plain words.
+func EscapeBooleanSearch(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		switch r {
+		case '+', '-', '<', '>', '(', ')', '~', '*', '"', '@':
+			return true
+		}
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}), " ")
+}
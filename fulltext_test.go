@@ -0,0 +1,32 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch_Against(t *testing.T) {
+	assert.Equal(t, `MATCH (p.title, p.body) AGAINST (? IN NATURAL LANGUAGE MODE)`, partToString(Match(Field("p.title"), Field("p.body")).Against(ParamString("shoes"), SearchNaturalLanguage)))
+	assert.Equal(t, `MATCH (p.title) AGAINST (? IN NATURAL LANGUAGE MODE WITH QUERY EXPANSION)`, partToString(Match(Field("p.title")).Against(ParamString("shoes"), SearchNaturalLanguageWithQueryExpansion)))
+	assert.Equal(t, `MATCH (p.title) AGAINST (? IN BOOLEAN MODE)`, partToString(Match(Field("p.title")).Against(ParamString("+shoes"), SearchBoolean)))
+	assert.Equal(t, `MATCH (p.title) AGAINST (? WITH QUERY EXPANSION)`, partToString(Match(Field("p.title")).Against(ParamString("shoes"), SearchWithQueryExpansion)))
+}
+
+func TestMatch_Relevance(t *testing.T) {
+	m := Match(Field("p.title"), Field("p.body"))
+	q := NewQueryFrom(Table("products").As("p")).
+		Select(Field("p.id"), m.Against(ParamString("red shoes"), SearchNaturalLanguage).As("score")).
+		Where(m.Against(ParamString("red shoes"), SearchNaturalLanguage)).
+		OrderBy(Alias("score"), OrderDirectionDesc)
+	s, v := q.Build()
+	assert.Equal(t, `SELECT p.id, MATCH (p.title, p.body) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM products AS p WHERE MATCH (p.title, p.body) AGAINST (? IN NATURAL LANGUAGE MODE) ORDER BY score DESC`, s)
+	assert.Equal(t, []interface{}{"red shoes", "red shoes"}, v)
+}
+
+func TestEscapeBooleanSearch(t *testing.T) {
+	assert.Equal(t, `red shoes`, EscapeBooleanSearch(`  +red  -shoes* `))
+	assert.Equal(t, `a b c d e`, EscapeBooleanSearch(`"a" (b) <c> ~d @e`))
+	assert.Equal(t, `chaussures été`, EscapeBooleanSearch(`chaussures été`))
+	assert.Equal(t, ``, EscapeBooleanSearch(`+-*`))
+}
@@ -0,0 +1,130 @@
+package query_builder
+
+// Func renders a call to an arbitrary SQL function, for anything the catalog
+// below does not cover.
+func Func(name string, args ...Part) Part {
+	p := make(parts, 0, 2+(len(args)<<1))
+	p = append(p, partString(name), partByte('('))
+	for i, v := range args {
+		if i != 0 {
+			p = append(p, partString(", "))
+		}
+		p = append(p, v)
+	}
+	p = append(p, partByte(')'))
+	return Part{p}
+}
+
+func Lower(v Part) Part {
+	return Func("LOWER", v)
+}
+
+func Upper(v Part) Part {
+	return Func("UPPER", v)
+}
+
+func Trim(v Part) Part {
+	return Func("TRIM", v)
+}
+
+func LTrim(v Part) Part {
+	return Func("LTRIM", v)
+}
+
+func RTrim(v Part) Part {
+	return Func("RTRIM", v)
+}
+
+func Substring(v, pos Part, length ...Part) Part {
+	return Func("SUBSTRING", append([]Part{v, pos}, length...)...)
+}
+
+func Replace(v, from, to Part) Part {
+	return Func("REPLACE", v, from, to)
+}
+
+func Length(v Part) Part {
+	return Func("LENGTH", v)
+}
+
+func CharLength(v Part) Part {
+	return Func("CHAR_LENGTH", v)
+}
+
+func Coalesce(vs ...Part) Part {
+	return Func("COALESCE", vs...)
+}
+
+func IfNull(v, def Part) Part {
+	return Func("IFNULL", v, def)
+}
+
+type IntervalUnit string
+
+const (
+	IntervalMicrosecond IntervalUnit = "MICROSECOND"
+	IntervalSecond      IntervalUnit = "SECOND"
+	IntervalMinute      IntervalUnit = "MINUTE"
+	IntervalHour        IntervalUnit = "HOUR"
+	IntervalDay         IntervalUnit = "DAY"
+	IntervalWeek        IntervalUnit = "WEEK"
+	IntervalMonth       IntervalUnit = "MONTH"
+	IntervalQuarter     IntervalUnit = "QUARTER"
+	IntervalYear        IntervalUnit = "YEAR"
+)
+
+func Interval(v Part, unit IntervalUnit) Part {
+	return Part{parts{partString("INTERVAL "), v, partString(" " + string(unit))}}
+}
+
+func DateAdd(date, v Part, unit IntervalUnit) Part {
+	return Func("DATE_ADD", date, Interval(v, unit))
+}
+
+func DateSub(date, v Part, unit IntervalUnit) Part {
+	return Func("DATE_SUB", date, Interval(v, unit))
+}
+
+func DateFormat(date, format Part) Part {
+	return Func("DATE_FORMAT", date, format)
+}
+
+func Date(v Part) Part {
+	return Func("DATE", v)
+}
+
+func Now() Part {
+	return Func("NOW")
+}
+
+func CurDate() Part {
+	return Func("CURDATE")
+}
+
+func UnixTimestamp(v ...Part) Part {
+	return Func("UNIX_TIMESTAMP", v...)
+}
+
+func FromUnixTime(v Part) Part {
+	return Func("FROM_UNIXTIME", v)
+}
+
+func Round(v Part, decimals ...Part) Part {
+	return Func("ROUND", append([]Part{v}, decimals...)...)
+}
+
+func Floor(v Part) Part {
+	return Func("FLOOR", v)
+}
+
+func Ceil(v Part) Part {
+	return Func("CEIL", v)
+}
+
+func Abs(v Part) Part {
+	return Func("ABS", v)
+}
+
+func Sum(v Part) Part {
+	return Func("SUM", v)
+}
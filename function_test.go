@@ -0,0 +1,88 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunc(t *testing.T) {
+	assert.Equal(t, `PI()`, partToString(Func("PI")))
+	assert.Equal(t, `GREATEST(field_name, ?)`, partToString(Func("GREATEST", Field("field_name"), ParamInt(1))))
+}
+
+func TestLower(t *testing.T) {
+	assert.Equal(t, `LOWER(field_name)`, partToString(Lower(Field("field_name"))))
+}
+
+func TestUpper(t *testing.T) {
+	assert.Equal(t, `UPPER(field_name)`, partToString(Upper(Field("field_name"))))
+}
+
+func TestTrim(t *testing.T) {
+	assert.Equal(t, `TRIM(field_name)`, partToString(Trim(Field("field_name"))))
+	assert.Equal(t, `LTRIM(field_name)`, partToString(LTrim(Field("field_name"))))
+	assert.Equal(t, `RTRIM(field_name)`, partToString(RTrim(Field("field_name"))))
+}
+
+func TestSubstring(t *testing.T) {
+	assert.Equal(t, `SUBSTRING(field_name, 2)`, partToString(Substring(Field("field_name"), ValueInt(2))))
+	assert.Equal(t, `SUBSTRING(field_name, 2, 5)`, partToString(Substring(Field("field_name"), ValueInt(2), ValueInt(5))))
+}
+
+func TestReplace(t *testing.T) {
+	assert.Equal(t, `REPLACE(field_name, 'a', 'b')`, partToString(Replace(Field("field_name"), ValueString("a"), ValueString("b"))))
+}
+
+func TestLength(t *testing.T) {
+	assert.Equal(t, `LENGTH(field_name)`, partToString(Length(Field("field_name"))))
+	assert.Equal(t, `CHAR_LENGTH(field_name)`, partToString(CharLength(Field("field_name"))))
+}
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, `COALESCE(field_name_1, field_name_2, 0)`, partToString(Coalesce(Field("field_name_1"), Field("field_name_2"), ValueInt(0))))
+	assert.Equal(t, `IFNULL(field_name, 0)`, partToString(IfNull(Field("field_name"), ValueInt(0))))
+}
+
+func TestInterval(t *testing.T) {
+	assert.Equal(t, `INTERVAL 3 DAY`, partToString(Interval(ValueInt(3), IntervalDay)))
+}
+
+func TestDateAdd(t *testing.T) {
+	assert.Equal(t, `DATE_ADD(field_name, INTERVAL ? MONTH)`, partToString(DateAdd(Field("field_name"), ParamInt(1), IntervalMonth)))
+}
+
+func TestDateSub(t *testing.T) {
+	assert.Equal(t, `DATE_SUB(NOW(), INTERVAL 90 MINUTE)`, partToString(DateSub(Now(), ValueInt(90), IntervalMinute)))
+}
+
+func TestDateFormat(t *testing.T) {
+	assert.Equal(t, `DATE_FORMAT(field_name, '%Y-%m')`, partToString(DateFormat(Field("field_name"), ValueString("%Y-%m"))))
+	assert.Equal(t, `DATE(field_name)`, partToString(Date(Field("field_name"))))
+}
+
+func TestNow(t *testing.T) {
+	assert.Equal(t, `NOW()`, partToString(Now()))
+	assert.Equal(t, `CURDATE()`, partToString(CurDate()))
+}
+
+func TestUnixTimestamp(t *testing.T) {
+	assert.Equal(t, `UNIX_TIMESTAMP()`, partToString(UnixTimestamp()))
+	assert.Equal(t, `UNIX_TIMESTAMP(field_name)`, partToString(UnixTimestamp(Field("field_name"))))
+	assert.Equal(t, `FROM_UNIXTIME(field_name)`, partToString(FromUnixTime(Field("field_name"))))
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, `ROUND(field_name)`, partToString(Round(Field("field_name"))))
+	assert.Equal(t, `ROUND(field_name, 2)`, partToString(Round(Field("field_name"), ValueInt(2))))
+}
+
+func TestFloor(t *testing.T) {
+	assert.Equal(t, `FLOOR(field_name)`, partToString(Floor(Field("field_name"))))
+	assert.Equal(t, `CEIL(field_name)`, partToString(Ceil(Field("field_name"))))
+	assert.Equal(t, `ABS(field_name)`, partToString(Abs(Field("field_name"))))
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, `SUM(field_name)`, partToString(Sum(Field("field_name"))))
+}
@@ -0,0 +1,123 @@
+package query_builder
+
+import (
+	"context"
+	"time"
+)
+
+// Event describes a statement run by an Executor. Rows is the number of rows
+// affected or read, -1 when unknown. SQL is empty when the statement failed
+// to build, Err holding the build error.
+type Event struct {
+	SQL      string
+	Args     []interface{}
+	Start    time.Time
+	Duration time.Duration
+	Rows     int64
+	Err      error
+}
+
+func (e *Event) Fingerprint() string {
+	return FingerprintSQL(e.SQL)
+}
+
+// Hook observes the statements run by an Executor. Before hooks are called in
+// order and may return a derived context, after hooks in reverse order.
+type Hook interface {
+	BeforeBuild(ctx context.Context, stmt Statement) context.Context
+	BeforeExecute(ctx context.Context, e *Event) context.Context
+	AfterExecute(ctx context.Context, e *Event)
+}
+
+// NopHook implements Hook doing nothing, to be embedded by hooks that only
+// need some of the methods.
+type NopHook struct{}
+
+func (NopHook) BeforeBuild(ctx context.Context, stmt Statement) context.Context {
+	return ctx
+}
+
+func (NopHook) BeforeExecute(ctx context.Context, e *Event) context.Context {
+	return ctx
+}
+
+func (NopHook) AfterExecute(ctx context.Context, e *Event) {}
+
+// Logger is the subset of *slog.Logger used by LogHook.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// LogHook logs every statement once run: failures at error level, others at
+// info level. Params are left out unless LogArgs is set.
+type LogHook struct {
+	NopHook
+	Logger  Logger
+	LogArgs bool
+}
+
+func NewLogHook(l Logger) *LogHook {
+	return &LogHook{Logger: l}
+}
+
+func (h *LogHook) AfterExecute(ctx context.Context, e *Event) {
+	attrs := []interface{}{"sql", e.SQL, "fingerprint", e.Fingerprint(), "duration", e.Duration, "rows", e.Rows}
+	if h.LogArgs {
+		attrs = append(attrs, "args", e.Args)
+	}
+	if e.Err != nil {
+		h.Logger.ErrorContext(ctx, "query failed", append(attrs, "error", e.Err)...)
+		return
+	}
+	h.Logger.InfoContext(ctx, "query", attrs...)
+}
+
+// Tracer and Span are meant to be adapted to a tracing library.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span started by a TraceHook for the statement
+// being run, if any.
+func SpanFromContext(ctx context.Context) (Span, bool) {
+	s, ok := ctx.Value(spanKey{}).(Span)
+	return s, ok
+}
+
+// TraceHook opens a span around each statement.
+type TraceHook struct {
+	NopHook
+	Tracer Tracer
+}
+
+func NewTraceHook(t Tracer) *TraceHook {
+	return &TraceHook{Tracer: t}
+}
+
+func (h *TraceHook) BeforeExecute(ctx context.Context, e *Event) context.Context {
+	ctx, span := h.Tracer.Start(ctx, "query")
+	span.SetAttribute("db.system", "mariadb")
+	span.SetAttribute("db.statement", e.SQL)
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (h *TraceHook) AfterExecute(ctx context.Context, e *Event) {
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+	span.SetAttribute("db.rows", e.Rows)
+	if e.Err != nil {
+		span.RecordError(e.Err)
+	}
+	span.End()
+}
@@ -0,0 +1,182 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	name  string
+	calls *[]string
+}
+
+type recordKey string
+
+func (h recordHook) BeforeBuild(ctx context.Context, stmt Statement) context.Context {
+	*h.calls = append(*h.calls, h.name+" build")
+	return context.WithValue(ctx, recordKey(h.name), true)
+}
+
+func (h recordHook) BeforeExecute(ctx context.Context, e *Event) context.Context {
+	*h.calls = append(*h.calls, h.name+" before "+e.SQL)
+	return ctx
+}
+
+func (h recordHook) AfterExecute(ctx context.Context, e *Event) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s after %d %v %v", h.name, e.Rows, e.Err, ctx.Value(recordKey(h.name))))
+}
+
+func TestExecutor(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	var calls []string
+	e := NewExecutor(db, recordHook{"a", &calls}, recordHook{"b", &calls})
+
+	_, err := e.ExecContext(ctx, NewQueryFrom(Table("t")).Select(All()).Where(Field("id").Eq(ParamInt(1))))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"a build", "b build",
+		"a before SELECT * FROM t WHERE id = ?", "b before SELECT * FROM t WHERE id = ?",
+		"b after 1 <nil> true", "a after 1 <nil> true",
+	}, calls)
+	assert.Equal(t, []driver.Value{int64(1)}, f.execs[0].args)
+
+	calls = nil
+	f.rows = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}, nil
+	}
+	rows, err := e.QueryContext(ctx, NewQueryFrom(Table("t")).Select(Field("id")))
+	assert.NoError(t, err)
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	assert.Equal(t, 3, n)
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, "a after 3 <nil> true", calls[len(calls)-1])
+	assert.Len(t, calls, 6)
+
+	calls = nil
+	var id int
+	assert.NoError(t, e.QueryRowContext(ctx, NewQueryFrom(Table("t")).Select(Field("id"))).Scan(&id))
+	assert.Equal(t, 1, id)
+	assert.Equal(t, "a after 1 <nil> true", calls[len(calls)-1])
+
+	calls = nil
+	f.rows = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, errors.New("boom")
+	}
+	assert.EqualError(t, e.QueryRowContext(ctx, NewQueryFrom(Table("t")).Select(Field("id"))).Scan(&id), "boom")
+	assert.Equal(t, "a after -1 boom true", calls[len(calls)-1])
+
+	calls = nil
+	_, err = e.ExecContext(ctx, NewDelete(Table("t")).Where(Field("id").InValues([]int{}, EmptyListError)))
+	assert.ErrorIs(t, err, ErrEmptyList)
+	assert.Equal(t, []string{
+		"a build", "b build",
+		"a before ", "b before ",
+		"b after -1 empty list of values for IN true", "a after -1 empty list of values for IN true",
+	}, calls)
+	assert.Len(t, f.execs, 1)
+}
+
+type testLogger struct {
+	sb strings.Builder
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	l.sb.WriteString(level + " " + msg)
+	for i := 0; i < len(args); i += 2 {
+		if args[i] == "duration" {
+			continue
+		}
+		fmt.Fprintf(&l.sb, " %s=%v", args[i], args[i+1])
+	}
+	l.sb.WriteString("\n")
+}
+
+func (l *testLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("INFO", msg, args)
+}
+
+func (l *testLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("ERROR", msg, args)
+}
+
+func TestLogHook(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	l := &testLogger{}
+	h := NewLogHook(l)
+	e := NewExecutor(db, h)
+
+	q := NewQueryFrom(Table("t")).Select(All()).Where(Field("id").Eq(ParamInt(1)))
+	_, err := e.ExecContext(ctx, q)
+	assert.NoError(t, err)
+	h.LogArgs = true
+	f.execErr = func(query string, args []driver.Value) error { return errors.New("boom") }
+	_, err = e.ExecContext(ctx, q)
+	assert.Error(t, err)
+	fp := q.Fingerprint()
+	assert.Equal(t, "INFO query sql=SELECT * FROM t WHERE id = ? fingerprint="+fp+" rows=1\n"+
+		"ERROR query failed sql=SELECT * FROM t WHERE id = ? fingerprint="+fp+" rows=-1 args=[1] error=boom\n", l.sb.String())
+}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attrs[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &testSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func TestTraceHook(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	tr := &testTracer{}
+	e := NewExecutor(db, NewTraceHook(tr))
+
+	_, err := e.ExecContext(ctx, NewQueryFrom(Table("t")).Select(All()))
+	assert.NoError(t, err)
+	f.execErr = func(query string, args []driver.Value) error { return errors.New("boom") }
+	_, err = e.ExecContext(ctx, NewQueryFrom(Table("t")).Select(All()))
+	assert.Error(t, err)
+
+	assert.Len(t, tr.spans, 2)
+	assert.Equal(t, &testSpan{
+		name:  "query",
+		attrs: map[string]interface{}{"db.system": "mariadb", "db.statement": "SELECT * FROM t", "db.rows": int64(1)},
+		ended: true,
+	}, tr.spans[0])
+	assert.EqualError(t, tr.spans[1].err, "boom")
+	assert.True(t, tr.spans[1].ended)
+	_, ok := SpanFromContext(ctx)
+	assert.False(t, ok)
+}
@@ -0,0 +1,331 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type InsertQuery struct {
+	verb      string
+	ignore    bool
+	table     Part
+	columns   []Part
+	values    ValueBuilder
+	returning []Part
+}
+
+func NewInsert(table Part) *InsertQuery {
+	return &InsertQuery{verb: "INSERT ", table: table}
+}
+
+// NewReplace starts a REPLACE, which deletes the rows conflicting with the
+// inserted ones on a unique key first.
+func NewReplace(table Part) *InsertQuery {
+	return &InsertQuery{verb: "REPLACE ", table: table}
+}
+
+func (iq *InsertQuery) Ignore() *InsertQuery {
+	if iq.verb == "REPLACE " {
+		panic("REPLACE doesn't support IGNORE")
+	}
+	iq.ignore = true
+	return iq
+}
+
+func (iq *InsertQuery) Columns(v ...Part) *InsertQuery {
+	iq.columns = append(iq.columns, v...)
+	return iq
+}
+
+func (iq *InsertQuery) Values(vb ValueBuilder) *InsertQuery {
+	iq.values = vb
+	return iq
+}
+
+// Returning makes the statement return v for each inserted row, MariaDB 10.5
+// and later.
+func (iq *InsertQuery) Returning(v ...Part) *InsertQuery {
+	iq.returning = append(iq.returning, v...)
+	return iq
+}
+
+func writeReturning(w *Writer, vs []Part) {
+	if len(vs) != 0 {
+		w.WriteString(" RETURNING ")
+		writeList(w, vs, ", ")
+	}
+}
+
+// buildPrefix writes the statement up to its values. When the table is
+// tenant-scoped, its tenant column is added to the columns and the returned
+// tenant must be added to every row.
+func (iq *InsertQuery) buildPrefix(w *Writer) Part {
+	w.WriteString(iq.verb)
+	if iq.ignore {
+		w.WriteString("IGNORE ")
+	}
+	w.WriteString("INTO ")
+	writeTableRef(w, iq.table)
+	var column string
+	var tenant Part
+	if t, ok := iq.table.builder.(partTable); ok {
+		column, tenant = tenantValue(w, t)
+	}
+	if column != "" {
+		if len(iq.columns) == 0 {
+			w.Fail(fmt.Errorf("%w: %s", ErrTenantInsert, column))
+		}
+		for _, c := range iq.columns {
+			if f, ok := c.builder.(partString); ok && strings.EqualFold(string(f), column) {
+				w.Fail(fmt.Errorf("%w: %s", ErrTenantInsert, column))
+			}
+		}
+	}
+	if len(iq.columns) != 0 {
+		w.WriteString(" (")
+		writeList(w, iq.columns, ", ")
+		if column != "" {
+			w.WriteString(", " + column)
+		}
+		w.WriteByte(')')
+	}
+	w.WriteByte(' ')
+	return tenant
+}
+
+// writeRow writes a row of the values, r being its parts between parentheses.
+func writeRow(w *Writer, r parts, tenant Part) {
+	r[:len(r)-1].BuildTo(w)
+	if !tenant.IsZero() {
+		w.WriteString(", ")
+		tenant.BuildTo(w)
+	}
+	w.WriteByte(')')
+}
+
+func (iq *InsertQuery) BuildTo(w *Writer) {
+	tenant := iq.buildPrefix(w)
+	if len(iq.values) != 0 {
+		w.WriteString("VALUES ")
+		for i, r := range iq.values.rows() {
+			if i != 0 {
+				w.WriteString(", ")
+			}
+			writeRow(w, r, tenant)
+		}
+	}
+	writeReturning(w, iq.returning)
+}
+
+func (iq *InsertQuery) Build() (string, []interface{}) {
+	return build(iq)
+}
+
+func (iq *InsertQuery) TryBuild() (string, []interface{}, error) {
+	return tryBuild(iq)
+}
+
+var ErrRowTooLarge = errors.New("row exceeds the batch limits")
+
+const (
+	DefaultMaxPlaceholders = 65535
+	DefaultMaxBytes        = 16 << 20
+)
+
+// BatchInsert splits the rows of an insert over as many statements as needed
+// to stay within the limits of MariaDB: the number of placeholders of a
+// prepared statement and max_allowed_packet.
+type BatchInsert struct {
+	insert          *InsertQuery
+	maxRows         int
+	maxPlaceholders int
+	maxBytes        int
+}
+
+func NewBatchInsert(iq *InsertQuery) *BatchInsert {
+	return &BatchInsert{insert: iq, maxPlaceholders: DefaultMaxPlaceholders, maxBytes: DefaultMaxBytes}
+}
+
+// MaxRows limits the number of rows per statement, 0 meaning no limit.
+func (bi *BatchInsert) MaxRows(n int) *BatchInsert {
+	bi.maxRows = n
+	return bi
+}
+
+func (bi *BatchInsert) MaxPlaceholders(n int) *BatchInsert {
+	bi.maxPlaceholders = n
+	return bi
+}
+
+// MaxBytes limits the estimated size of each statement, SQL and params, which
+// should stay under max_allowed_packet.
+func (bi *BatchInsert) MaxBytes(n int) *BatchInsert {
+	bi.maxBytes = n
+	return bi
+}
+
+// rawStatement is a statement already built, or failed to build.
+type rawStatement struct {
+	s    string
+	args []interface{}
+	err  error
+}
+
+func (rs rawStatement) Build() (string, []interface{}) {
+	if rs.err != nil {
+		panic(rs.err)
+	}
+	return rs.s, rs.args
+}
+
+func (rs rawStatement) TryBuild() (string, []interface{}, error) {
+	return rs.s, rs.args, rs.err
+}
+
+// rows returns the rows of vb, each one made of the parts between its
+// parentheses.
+func (vb ValueBuilder) rows() []parts {
+	var rows []parts
+	start := -1
+	for i, v := range vb {
+		switch v {
+		case partByte('('):
+			start = i
+		case partByte(')'):
+			rows = append(rows, parts(vb[start:i+1]))
+		}
+	}
+	return rows
+}
+
+// argSize estimates the size taken by v in a packet.
+func argSize(v interface{}) int {
+	switch t := v.(type) {
+	case string:
+		return len(t) + 9
+	case []byte:
+		return len(t) + 9
+	case time.Time:
+		return 12
+	}
+	return 8
+}
+
+// Statements renders the batch. Each row is built once, and rows are then
+// gathered into statements until a limit would be exceeded.
+func (bi *BatchInsert) Statements() ([]Statement, error) {
+	return bi.StatementsContext(context.Background())
+}
+
+// StatementsContext is Statements with the tenant of ctx, if any.
+func (bi *BatchInsert) StatementsContext(ctx context.Context) ([]Statement, error) {
+	var prefix, suffix, w, row Writer
+	tenantValue, _ := TenantFromContext(ctx)
+	prefix.tenant, row.tenant = tenantValue, tenantValue
+	tenant := bi.insert.buildPrefix(&prefix)
+	prefix.WriteString("VALUES ")
+	writeReturning(&suffix, bi.insert.returning)
+	if prefix.err != nil {
+		return nil, prefix.err
+	} else if suffix.err != nil {
+		return nil, suffix.err
+	}
+
+	var stmts []Statement
+	rows, size := 0, 0
+	flush := func() {
+		if rows != 0 {
+			w.Write(suffix.buf)
+			w.args = append(w.args, suffix.args...)
+			stmts = append(stmts, rawStatement{s: w.String(), args: append([]interface{}(nil), w.args...)})
+		}
+		w.Reset()
+		w.Write(prefix.buf)
+		w.args = append(w.args, prefix.args...)
+		rows, size = 0, w.Len()+suffix.Len()
+		for _, v := range w.args {
+			size += argSize(v)
+		}
+		for _, v := range suffix.args {
+			size += argSize(v)
+		}
+	}
+	flush()
+	for i, r := range bi.insert.values.rows() {
+		row.Reset()
+		writeRow(&row, r, tenant)
+		if row.err != nil {
+			return nil, row.err
+		}
+		rowSize := row.Len() + 2
+		for _, v := range row.args {
+			rowSize += argSize(v)
+		}
+		fits := func() bool {
+			return (bi.maxRows <= 0 || rows < bi.maxRows) &&
+				(bi.maxPlaceholders <= 0 || len(w.args)+len(row.args)+len(suffix.args) <= bi.maxPlaceholders) &&
+				(bi.maxBytes <= 0 || size+rowSize <= bi.maxBytes)
+		}
+		if !fits() {
+			flush()
+			if !fits() {
+				return nil, fmt.Errorf("%w: row %d", ErrRowTooLarge, i)
+			}
+		}
+		if rows != 0 {
+			w.WriteString(", ")
+		}
+		w.Write(row.buf)
+		w.args = append(w.args, row.args...)
+		rows++
+		size += rowSize
+	}
+	flush()
+	return stmts, nil
+}
+
+// Exec runs the statements of the batch one after the other through e and
+// returns the total number of rows affected. A batch failing to build is
+// reported to the hooks of e as a statement failing to build.
+func (bi *BatchInsert) Exec(ctx context.Context, e *Executor) (int64, error) {
+	stmts, err := bi.StatementsContext(ctx)
+	if err != nil {
+		e.ExecContext(ctx, rawStatement{err: err})
+		return 0, err
+	}
+	var n int64
+	for _, stmt := range stmts {
+		res, err := e.ExecContext(ctx, stmt)
+		if err != nil {
+			return n, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return n, err
+		}
+		n += affected
+	}
+	return n, nil
+}
+
+// ExecTx runs the batch in a transaction, so that it is inserted as a whole
+// or not at all, calling hooks around each statement.
+func (bi *BatchInsert) ExecTx(ctx context.Context, db *sql.DB, hooks ...Hook) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, err := bi.Exec(ctx, NewExecutor(tx, hooks...))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,150 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertQuery(t *testing.T) {
+	vb := NewValueBuilder()
+	vb.Append(ParamInt(1), ParamString("a"))
+	vb.Append(ParamInt(2), Null())
+	s, args := NewInsert(Table("t")).Ignore().Columns(Field("id"), Field("name")).Values(vb).Build()
+	assert.Equal(t, "INSERT IGNORE INTO t (id, name) VALUES (?, ?), (?, NULL)", s)
+	assert.Equal(t, []interface{}{1, "a", 2}, args)
+}
+
+func batchRows(n int) ValueBuilder {
+	vb := NewValueBuilder()
+	for i := 0; i < n; i++ {
+		vb.Append(ParamInt(i), ParamString("name"), Now())
+	}
+	return vb
+}
+
+func statementsToStrings(stmts []Statement) []string {
+	var ss []string
+	for _, v := range stmts {
+		ss = append(ss, builderToString(v))
+	}
+	return ss
+}
+
+func TestBatchInsert(t *testing.T) {
+	iq := NewInsert(Table("t")).Columns(Field("id"), Field("name"), Field("created_at")).Values(batchRows(5))
+
+	stmts, err := NewBatchInsert(iq).Statements()
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 1)
+	s, _ := iq.Build()
+	assert.Equal(t, []string{s}, statementsToStrings(stmts))
+
+	stmts, err = NewBatchInsert(iq).MaxRows(2).Statements()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"INSERT INTO t (id, name, created_at) VALUES (?, ?, NOW()), (?, ?, NOW())",
+		"INSERT INTO t (id, name, created_at) VALUES (?, ?, NOW()), (?, ?, NOW())",
+		"INSERT INTO t (id, name, created_at) VALUES (?, ?, NOW())",
+	}, statementsToStrings(stmts))
+	_, args := stmts[2].Build()
+	assert.Equal(t, []interface{}{4, "name"}, args)
+
+	stmts, err = NewBatchInsert(iq).MaxPlaceholders(7).Statements()
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 2)
+	_, args = stmts[0].Build()
+	assert.Len(t, args, 6)
+
+	// The prefix takes 44 bytes, each row 15 and its params 8 + 13.
+	stmts, err = NewBatchInsert(iq).MaxBytes(44 + 2*36).Statements()
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 3)
+
+	_, err = NewBatchInsert(iq).MaxBytes(60).Statements()
+	assert.ErrorIs(t, err, ErrRowTooLarge)
+	assert.EqualError(t, err, "row exceeds the batch limits: row 0")
+
+	stmts, err = NewBatchInsert(NewInsert(Table("t")).Values(NewValueBuilder())).Statements()
+	assert.NoError(t, err)
+	assert.Empty(t, stmts)
+}
+
+func TestBatchInsert_Exec(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	iq := NewInsert(Table("t")).Columns(Field("id"), Field("name"), Field("created_at")).Values(batchRows(5))
+
+	n, err := NewBatchInsert(iq).MaxRows(2).ExecTx(ctx, db)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Len(t, f.execs, 3)
+	assert.Equal(t, []driver.Value{int64(4), "name"}, f.execs[2].args)
+	assert.Equal(t, 1, f.begins)
+	assert.Equal(t, 1, f.commits)
+
+	f.execErr = func(query string, args []driver.Value) error {
+		if args[0] == int64(2) {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	_, err = NewBatchInsert(iq).MaxRows(2).ExecTx(ctx, db)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 1, f.rollbacks)
+	assert.Equal(t, 1, f.commits)
+
+	f.execErr = nil
+	f.commits, f.execs = 0, nil
+	var calls []string
+	n, err = NewBatchInsert(iq).MaxRows(2).ExecTx(ctx, db, recordHook{"a", &calls})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "a after 1 <nil> true", calls[2])
+	assert.Len(t, calls, 9)
+
+	calls = nil
+	n, err = NewBatchInsert(iq).MaxBytes(60).Exec(ctx, NewExecutor(db, recordHook{"a", &calls}))
+	assert.ErrorIs(t, err, ErrRowTooLarge)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, []string{"a build", "a before ", "a after -1 row exceeds the batch limits: row 0 true"}, calls)
+	assert.Len(t, f.execs, 3)
+
+	f.commitErr = errors.New("commit failed")
+	n, err = NewBatchInsert(iq).MaxRows(2).ExecTx(ctx, db)
+	assert.EqualError(t, err, "commit failed")
+	assert.Equal(t, int64(0), n)
+}
+
+func BenchmarkBatchInsert_Statements10k(b *testing.B) {
+	iq := NewInsert(Table("t")).Columns(Field("id"), Field("name"), Field("created_at")).Values(batchRows(10000))
+	bi := NewBatchInsert(iq).MaxPlaceholders(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bi.Statements()
+	}
+}
+
+func TestInsertQuery_Returning(t *testing.T) {
+	vb := NewValueBuilder()
+	vb.Append(ParamString("a"))
+	vb.Append(ParamString("b"))
+	iq := NewInsert(Table("t")).Columns(Field("name")).Values(vb).Returning(Field("id"), Field("created_at"))
+	assert.Equal(t, "INSERT INTO t (name) VALUES (?), (?) RETURNING id, created_at", builderToString(iq))
+
+	stmts, err := NewBatchInsert(iq).MaxRows(1).Statements()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"INSERT INTO t (name) VALUES (?) RETURNING id, created_at",
+		"INSERT INTO t (name) VALUES (?) RETURNING id, created_at",
+	}, statementsToStrings(stmts))
+
+	s, args := NewReplace(Table("t")).Columns(Field("id"), Field("name")).Values(vb).Returning(All()).Build()
+	assert.Equal(t, "REPLACE INTO t (id, name) VALUES (?), (?) RETURNING *", s)
+	assert.Equal(t, []interface{}{"a", "b"}, args)
+	assert.Panics(t, func() { NewReplace(Table("t")).Ignore() })
+}
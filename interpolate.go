@@ -0,0 +1,159 @@
+package query_builder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const debugMarker = "/* DEBUG: params interpolated, not for execution */ "
+
+// Interpolate replaces each placeholder of s with its argument rendered as a
+// MariaDB literal. The result is meant for reading and pasting in a console
+// while debugging; always execute the built SQL with its params.
+func Interpolate(s string, args []interface{}) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(s) + len(args)*8)
+	i := 0
+	for _, t := range tokenize(s) {
+		if t.kind != tokenParam {
+			sb.WriteString(t.s)
+			continue
+		}
+		if i >= len(args) {
+			return "", fmt.Errorf("missing argument for placeholder %d", i+1)
+		}
+		l, err := literal(args[i])
+		if err != nil {
+			return "", fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		sb.WriteString(l)
+		i++
+	}
+	if i != len(args) {
+		return "", fmt.Errorf("%d arguments for %d placeholders", len(args), i)
+	}
+	return sb.String(), nil
+}
+
+func literal(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "NULL", nil
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL", nil
+		}
+		dv, err := t.Value()
+		if err != nil {
+			return "", err
+		}
+		return literal(dv)
+	case sql.NamedArg:
+		// Unbound named params show up by name.
+		if t.Value == nil {
+			return ":" + t.Name, nil
+		}
+		return literal(t.Value)
+	case string:
+		return quoteString(t), nil
+	case []byte:
+		if t == nil {
+			return "NULL", nil
+		}
+		return "X'" + hex.EncodeToString(t) + "'", nil
+	case bool:
+		if t {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case time.Time:
+		return "'" + t.Format("2006-01-02 15:04:05.999999") + "'", nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "NULL", nil
+		}
+		return literal(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return quoteString(rv.String()), nil
+	case reflect.Bool:
+		return literal(rv.Bool())
+	}
+	return "", fmt.Errorf("can't render %T as a literal", v)
+}
+
+func debugString(s string, args []interface{}, err error) string {
+	if err != nil {
+		return debugMarker + "/* " + err.Error() + " */"
+	}
+	is, err := Interpolate(s, args)
+	if err != nil {
+		return debugMarker + s + " /* " + err.Error() + " */"
+	}
+	return debugMarker + is
+}
+
+// DebugString returns the query with its params inlined, prefixed with a
+// comment marking it as not meant for execution. Named params and the tenant
+// show up by name.
+func (q *Query) DebugString() string {
+	return debugString(buildUnresolved(q))
+}
+
+func (p Part) DebugString() string {
+	if p.IsZero() {
+		return ""
+	}
+	return debugString(buildUnresolved(p))
+}
+
+func format(f fmt.State, verb rune, b builder) {
+	s, args, err := buildUnresolved(b)
+	if err != nil {
+		s = "/* " + err.Error() + " */"
+	}
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			s = debugString(s, args, err)
+		}
+		io.WriteString(f, s)
+	case 's':
+		io.WriteString(f, s)
+	case 'q':
+		io.WriteString(f, strconv.Quote(s))
+	default:
+		fmt.Fprintf(f, "%%!%c(%T)", verb, b)
+	}
+}
+
+// Format prints the built SQL with %s, %v and %q; %+v inlines the params as
+// DebugString does. A failed build prints its error as a comment.
+func (q *Query) Format(f fmt.State, verb rune) {
+	format(f, verb, q)
+}
+
+func (p Part) Format(f fmt.State, verb rune) {
+	if p.IsZero() {
+		io.WriteString(f, "<nil>")
+		return
+	}
+	format(f, verb, p)
+}
@@ -0,0 +1,60 @@
+package query_builder
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStatus int
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2001, 2, 3, 4, 5, 6, 7000, time.UTC)
+	i := 42
+	var np *int
+	s, err := Interpolate(`SELECT ?, '?', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? FROM t`, []interface{}{
+		"it's", ts, []byte{0xde, 0xad}, nil, true, false, -3, uint8(7), 1.5,
+		sql.NullString{String: "x", Valid: true}, sql.NullInt64{}, &i, np, testStatus(2), []byte(nil),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT 'it\'s', '?', '2001-02-03 04:05:06.000007', X'dead', NULL, TRUE, FALSE, -3, 7, 1.5, 'x', NULL, 42, NULL, 2, NULL FROM t`, s)
+	assert.Equal(t, `'a\\b\n'`, mustInterpolate(t, `?`, "a\\b\n"))
+	assert.Equal(t, `'2001-02-03 04:05:06'`, mustInterpolate(t, `?`, time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)))
+	assert.Equal(t, `0.1, 0.1`, mustInterpolate(t, `?, ?`, float32(0.1), 0.1))
+}
+
+func mustInterpolate(t *testing.T, s string, args ...interface{}) string {
+	is, err := Interpolate(s, args)
+	assert.NoError(t, err)
+	return is
+}
+
+func TestInterpolate_Errors(t *testing.T) {
+	_, err := Interpolate(`SELECT ?, ?`, []interface{}{1})
+	assert.EqualError(t, err, "missing argument for placeholder 2")
+	_, err = Interpolate(`SELECT ?`, []interface{}{1, 2})
+	assert.EqualError(t, err, "2 arguments for 1 placeholders")
+	_, err = Interpolate(`SELECT ?`, []interface{}{struct{}{}})
+	assert.EqualError(t, err, "argument 1: can't render struct {} as a literal")
+}
+
+func TestQuery_DebugString(t *testing.T) {
+	q := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").Eq(ParamString("x")), Field("b").In(ParamInts([]int{1, 2})))
+	assert.Equal(t, `/* DEBUG: params interpolated, not for execution */ SELECT * FROM t WHERE a = 'x' AND b IN (1, 2)`, q.DebugString())
+	assert.Equal(t, `/* DEBUG: params interpolated, not for execution */ a = 'x'`, Field("a").Eq(ParamString("x")).DebugString())
+	assert.Equal(t, `/* DEBUG: params interpolated, not for execution */ a = ? /* argument 1: can't render struct {} as a literal */`, Field("a").Eq(Param(struct{}{})).DebugString())
+}
+
+func TestQuery_Format(t *testing.T) {
+	q := NewQueryFrom(Table("t")).Select(All()).Where(Field("a").Eq(ParamInt(1)))
+	assert.Equal(t, `SELECT * FROM t WHERE a = ?`, fmt.Sprintf("%s", q))
+	assert.Equal(t, `SELECT * FROM t WHERE a = ?`, fmt.Sprintf("%v", q))
+	assert.Equal(t, `"SELECT * FROM t WHERE a = ?"`, fmt.Sprintf("%q", q))
+	assert.Equal(t, `/* DEBUG: params interpolated, not for execution */ SELECT * FROM t WHERE a = 1`, fmt.Sprintf("%+v", q))
+	assert.Equal(t, `a = ?`, fmt.Sprintf("%v", Field("a").Eq(ParamInt(1))))
+	assert.Equal(t, `/* DEBUG: params interpolated, not for execution */ a = 1`, fmt.Sprintf("%+v", Field("a").Eq(ParamInt(1))))
+	assert.Equal(t, `<nil>`, fmt.Sprintf("%v", Part{}))
+}
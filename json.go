@@ -0,0 +1,158 @@
+package query_builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JsonPath builds a JSON path expression, quoting object keys as needed so
+// that user supplied keys can't change the shape of the path.
+type JsonPath struct {
+	s string
+}
+
+func JsonRoot() JsonPath {
+	return JsonPath{"$"}
+}
+
+func (jp JsonPath) Key(k string) JsonPath {
+	return JsonPath{jp.s + "." + quoteJsonKey(k)}
+}
+
+func (jp JsonPath) AnyKey() JsonPath {
+	return JsonPath{jp.s + ".*"}
+}
+
+func (jp JsonPath) Index(i int) JsonPath {
+	return JsonPath{jp.s + "[" + strconv.Itoa(i) + "]"}
+}
+
+func (jp JsonPath) AnyIndex() JsonPath {
+	return JsonPath{jp.s + "[*]"}
+}
+
+func (jp JsonPath) String() string {
+	return jp.s
+}
+
+func (jp JsonPath) Part() Part {
+	return Part{partString(quoteString(jp.s))}
+}
+
+func quoteJsonKey(k string) string {
+	bare := k != ""
+	for i := 0; i < len(k) && bare; i++ {
+		c := k[i]
+		bare = c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9'
+	}
+	if bare {
+		return k
+	}
+	var sb strings.Builder
+	sb.Grow(len(k) + 2)
+	sb.WriteByte('"')
+	for i := 0; i < len(k); i++ {
+		if c := k[i]; c == '"' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(k[i])
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func JsonValue(v, path Part) Part {
+	return Func("JSON_VALUE", v, path)
+}
+
+func JsonQuery(v, path Part) Part {
+	return Func("JSON_QUERY", v, path)
+}
+
+func JsonUnquote(v Part) Part {
+	return Func("JSON_UNQUOTE", v)
+}
+
+func JsonContains(doc, candidate Part, path ...Part) Part {
+	return Func("JSON_CONTAINS", append([]Part{doc, candidate}, path...)...)
+}
+
+func JsonLength(v Part, path ...Part) Part {
+	return Func("JSON_LENGTH", append([]Part{v}, path...)...)
+}
+
+func JsonValid(v Part) Part {
+	return Func("JSON_VALID", v)
+}
+
+// JsonObject takes alternating keys and values.
+func JsonObject(kvs ...Part) Part {
+	if len(kvs)&1 != 0 {
+		panic("JsonObject expects key value pairs")
+	}
+	return Func("JSON_OBJECT", kvs...)
+}
+
+func JsonArray(vs ...Part) Part {
+	return Func("JSON_ARRAY", vs...)
+}
+
+func JsonArrayAgg(v Part) Part {
+	return Func("JSON_ARRAYAGG", v)
+}
+
+func JsonObjectAgg(k, v Part) Part {
+	return Func("JSON_OBJECTAGG", k, v)
+}
+
+func jsonModify(name string, doc Part, pvs []Part) Part {
+	if len(pvs) == 0 || len(pvs)&1 != 0 {
+		panic(name + " expects path value pairs")
+	}
+	return Func(name, append([]Part{doc}, pvs...)...)
+}
+
+// JsonSet takes alternating paths and values, as do JsonInsert and
+// JsonReplace.
+func JsonSet(doc Part, pvs ...Part) Part {
+	return jsonModify("JSON_SET", doc, pvs)
+}
+
+func JsonInsert(doc Part, pvs ...Part) Part {
+	return jsonModify("JSON_INSERT", doc, pvs)
+}
+
+func JsonReplace(doc Part, pvs ...Part) Part {
+	return jsonModify("JSON_REPLACE", doc, pvs)
+}
+
+func JsonRemove(doc Part, paths ...Part) Part {
+	return Func("JSON_REMOVE", append([]Part{doc}, paths...)...)
+}
+
+func jsonColumns(columns []Part) Part {
+	return Part{parts{partString("COLUMNS("), List(columns...), partByte(')')}}
+}
+
+// JsonTable renders a JSON_TABLE source to be used in From or a join. Columns
+// are built with JsonColumn, JsonExistsColumn, JsonOrdinalityColumn and
+// JsonNestedColumns.
+func JsonTable(doc Part, path JsonPath, columns ...Part) Part {
+	return Func("JSON_TABLE", doc, Part{parts{path.Part(), partByte(' '), jsonColumns(columns)}})
+}
+
+func JsonColumn(name string, t SQLType, path JsonPath) Part {
+	return Part{parts{partString(name + " " + t.s + " PATH "), path.Part()}}
+}
+
+func JsonExistsColumn(name string, t SQLType, path JsonPath) Part {
+	return Part{parts{partString(name + " " + t.s + " EXISTS PATH "), path.Part()}}
+}
+
+func JsonOrdinalityColumn(name string) Part {
+	return Part{partString(name + " FOR ORDINALITY")}
+}
+
+func JsonNestedColumns(path JsonPath, columns ...Part) Part {
+	return Part{parts{partString("NESTED PATH "), path.Part(), partByte(' '), jsonColumns(columns)}}
+}
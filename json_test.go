@@ -0,0 +1,80 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonPath(t *testing.T) {
+	assert.Equal(t, `$`, JsonRoot().String())
+	assert.Equal(t, `$.a.b_2[3]`, JsonRoot().Key("a").Key("b_2").Index(3).String())
+	assert.Equal(t, `$.*[*]`, JsonRoot().AnyKey().AnyIndex().String())
+	assert.Equal(t, `$."first name"."2nd"."a\"b\\c"`, JsonRoot().Key("first name").Key("2nd").Key(`a"b\c`).String())
+	assert.Equal(t, `$.""`, JsonRoot().Key("").String())
+}
+
+func TestJsonPath_Part(t *testing.T) {
+	assert.Equal(t, `'$.a'`, partToString(JsonRoot().Key("a").Part()))
+	assert.Equal(t, `'$."it\'s"'`, partToString(JsonRoot().Key("it's").Part()))
+	assert.Equal(t, `'$."a\\"b"'`, partToString(JsonRoot().Key(`a"b`).Part()))
+}
+
+func TestJsonValue(t *testing.T) {
+	assert.Equal(t, `JSON_VALUE(field_name, '$.a')`, partToString(JsonValue(Field("field_name"), JsonRoot().Key("a").Part())))
+	assert.Equal(t, `JSON_QUERY(field_name, '$.a')`, partToString(JsonQuery(Field("field_name"), JsonRoot().Key("a").Part())))
+}
+
+func TestJsonUnquote(t *testing.T) {
+	assert.Equal(t, `JSON_UNQUOTE(JSON_EXTRACT(field_name, '$.a'))`, partToString(JsonUnquote(JsonExtract(Field("field_name"), JsonRoot().Key("a").Part()))))
+}
+
+func TestJsonContains(t *testing.T) {
+	assert.Equal(t, `JSON_CONTAINS(field_name, ?)`, partToString(JsonContains(Field("field_name"), Param(`"a"`))))
+	assert.Equal(t, `JSON_CONTAINS(field_name, ?, '$.tags')`, partToString(JsonContains(Field("field_name"), Param(`"a"`), JsonRoot().Key("tags").Part())))
+}
+
+func TestJsonLength(t *testing.T) {
+	assert.Equal(t, `JSON_LENGTH(field_name)`, partToString(JsonLength(Field("field_name"))))
+	assert.Equal(t, `JSON_VALID(field_name)`, partToString(JsonValid(Field("field_name"))))
+}
+
+func TestJsonObject(t *testing.T) {
+	assert.Equal(t, `JSON_OBJECT('id', t.id, 'name', t.name)`, partToString(JsonObject(ValueString("id"), Field("t.id"), ValueString("name"), Field("t.name"))))
+	assert.Panics(t, func() { JsonObject(ValueString("id")) })
+}
+
+func TestJsonArray(t *testing.T) {
+	assert.Equal(t, `JSON_ARRAY(1, 2)`, partToString(JsonArray(ValueInt(1), ValueInt(2))))
+}
+
+func TestJsonArrayAgg(t *testing.T) {
+	assert.Equal(t, `JSON_ARRAYAGG(t.id)`, partToString(JsonArrayAgg(Field("t.id"))))
+	assert.Equal(t, `JSON_OBJECTAGG(t.id, t.name)`, partToString(JsonObjectAgg(Field("t.id"), Field("t.name"))))
+}
+
+func TestJsonSet(t *testing.T) {
+	s, v := JsonSet(Field("field_name"), JsonRoot().Key("a").Part(), ParamInt(1), JsonRoot().Key("b").Part(), ParamInt(2)).Build()
+	assert.Equal(t, `JSON_SET(field_name, '$.a', ?, '$.b', ?)`, s)
+	assert.Equal(t, []interface{}{1, 2}, v)
+	assert.Equal(t, `JSON_INSERT(field_name, '$.a', 1)`, partToString(JsonInsert(Field("field_name"), JsonRoot().Key("a").Part(), ValueInt(1))))
+	assert.Equal(t, `JSON_REPLACE(field_name, '$.a', 1)`, partToString(JsonReplace(Field("field_name"), JsonRoot().Key("a").Part(), ValueInt(1))))
+	assert.Panics(t, func() { JsonSet(Field("field_name")) })
+	assert.Panics(t, func() { JsonReplace(Field("field_name"), JsonRoot().Part()) })
+}
+
+func TestJsonRemove(t *testing.T) {
+	assert.Equal(t, `JSON_REMOVE(field_name, '$.a', '$.b')`, partToString(JsonRemove(Field("field_name"), JsonRoot().Key("a").Part(), JsonRoot().Key("b").Part())))
+}
+
+func TestJsonTable(t *testing.T) {
+	jt := JsonTable(Field("t.doc"), JsonRoot().Key("items").AnyIndex(),
+		JsonOrdinalityColumn("idx"),
+		JsonColumn("sku", TypeVarchar(32), JsonRoot().Key("sku")),
+		JsonExistsColumn("has_price", TypeInt(), JsonRoot().Key("price")),
+		JsonNestedColumns(JsonRoot().Key("tags").AnyIndex(), JsonColumn("tag", TypeText(), JsonRoot())),
+	).As("jt")
+	q := NewQueryFrom(Table("t")).Select(Field("jt.sku")).InnerJoin(jt, True())
+	s, _ := q.Build()
+	assert.Equal(t, `SELECT jt.sku FROM t INNER JOIN JSON_TABLE(t.doc, '$.items[*]' COLUMNS(idx FOR ORDINALITY, sku VARCHAR(32) PATH '$.sku', has_price INT EXISTS PATH '$.price', NESTED PATH '$.tags[*]' COLUMNS(tag TEXT PATH '$'))) AS jt ON TRUE`, s)
+}
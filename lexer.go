@@ -0,0 +1,102 @@
+package query_builder
+
+type tokenKind int
+
+const (
+	tokenSpace tokenKind = iota
+	tokenComment
+	tokenWord
+	tokenNumber
+	tokenString
+	tokenQuoted
+	tokenParam
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	s    string
+}
+
+func isWordByte(c byte, first bool) bool {
+	return c == '_' || c == '$' || c == '@' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 ||
+		!first && (c == '.' || c >= '0' && c <= '9')
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// tokenize splits built SQL into tokens, enough to find parameters, literals
+// and keywords without being confused by their content. Concatenating the
+// tokens gives back s.
+func tokenize(s string) []token {
+	var ts []token
+	for i := 0; i < len(s); {
+		c, j := s[i], i+1
+		kind := tokenPunct
+		switch {
+		case isSpaceByte(c):
+			kind = tokenSpace
+			for j < len(s) && isSpaceByte(s[j]) {
+				j++
+			}
+		case c == '#', c == '-' && j+1 < len(s) && s[j] == '-' && isSpaceByte(s[j+1]):
+			kind = tokenComment
+			for j < len(s) && s[j] != '\n' {
+				j++
+			}
+		case c == '/' && j < len(s) && s[j] == '*':
+			kind = tokenComment
+			j++
+			for j < len(s) && !(s[j] == '/' && s[j-1] == '*' && j-1 > i+1) {
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+		case c == '\'':
+			kind = tokenString
+			j = scanQuoted(s, j, c, true)
+		case c == '"' || c == '`':
+			kind = tokenQuoted
+			j = scanQuoted(s, j, c, c == '"')
+		case c == '?':
+			kind = tokenParam
+		case c >= '0' && c <= '9':
+			kind = tokenNumber
+			for j < len(s) && isWordByte(s[j], false) {
+				j++
+			}
+		case isWordByte(c, true):
+			kind = tokenWord
+			for j < len(s) && isWordByte(s[j], false) {
+				j++
+			}
+		}
+		ts = append(ts, token{kind, s[i:j]})
+		i = j
+	}
+	return ts
+}
+
+// scanQuoted returns the end of the quoted text starting at i, just after the
+// opening quote q. A doubled quote stands for the quote itself.
+func scanQuoted(s string, i int, q byte, backslash bool) int {
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case q:
+			if i+1 < len(s) && s[i+1] == q {
+				i++
+			} else {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return i
+}
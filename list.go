@@ -0,0 +1,107 @@
+package query_builder
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	ErrEmptyList  = errors.New("empty list of values")
+	ErrZeroPart   = errors.New("zero Part used in an expression")
+	ErrTableName  = errors.New("table name with an inline alias")
+	ErrCaseNoWhen = errors.New("CASE expects at least one WHEN branch")
+)
+
+// EmptyListPolicy tells InValues and NotInValues what to render for an empty
+// list, which IN doesn't allow.
+type EmptyListPolicy int
+
+const (
+	// EmptyListFalse renders IN as FALSE and NOT IN as TRUE.
+	EmptyListFalse EmptyListPolicy = iota
+	// EmptyListError fails the build with ErrEmptyList.
+	EmptyListError
+	// EmptyListSkip drops the predicate: it returns a zero Part, which Where,
+	// And, Or, Xor, Not and Cond drop. Anywhere else, such as an operand,
+	// function argument or CASE branch, it fails the build with ErrZeroPart.
+	EmptyListSkip
+)
+
+type partError struct {
+	err error
+}
+
+func (e partError) BuildTo(w *Writer) {
+	w.Fail(e.err)
+}
+
+// listValues returns the elements of the slice or array vs as parts. Parts are
+// used as they are, other values as params.
+func listValues(vs interface{}) []Part {
+	rv := reflect.ValueOf(vs)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		panic(fmt.Sprintf("expected a slice, got %T", vs))
+	}
+	ps := make([]Part, rv.Len())
+	for i := range ps {
+		v := rv.Index(i).Interface()
+		if p, ok := v.(Part); ok {
+			ps[i] = p
+		} else {
+			ps[i] = Param(v)
+		}
+	}
+	return ps
+}
+
+// sliceParam returns the values of a single slice param, which In expands.
+// Byte slices, json.RawMessage included, and driver.Valuers are single values
+// rather than lists.
+func sliceParam(vs []Part) ([]Part, bool) {
+	if len(vs) != 1 {
+		return nil, false
+	}
+	pp, ok := vs[0].builder.(partParam)
+	if !ok || pp.v == nil {
+		return nil, false
+	}
+	if _, ok := pp.v.(driver.Valuer); ok {
+		return nil, false
+	}
+	t := reflect.TypeOf(pp.v)
+	if k := t.Kind(); k != reflect.Slice && k != reflect.Array || t.Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	return listValues(pp.v), true
+}
+
+func (p Part) inValues(op string, vs []Part, policy []EmptyListPolicy) Part {
+	if len(vs) != 0 {
+		return p.in(op, vs)
+	}
+	if len(policy) == 0 {
+		policy = []EmptyListPolicy{EmptyListFalse}
+	}
+	switch policy[0] {
+	case EmptyListError:
+		return Part{partError{fmt.Errorf("%w for %s", ErrEmptyList, op)}}
+	case EmptyListSkip:
+		return Part{}
+	}
+	if op == "IN" {
+		return False()
+	}
+	return True()
+}
+
+// InValues expands the slice vs to one placeholder per element. An empty slice
+// is handled according to policy, EmptyListFalse by default.
+func (p Part) InValues(vs interface{}, policy ...EmptyListPolicy) Part {
+	return p.inValues("IN", listValues(vs), policy)
+}
+
+func (p Part) NotInValues(vs interface{}, policy ...EmptyListPolicy) Part {
+	return p.inValues("NOT IN", listValues(vs), policy)
+}
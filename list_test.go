@@ -0,0 +1,82 @@
+package query_builder
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPart_InValues(t *testing.T) {
+	s, args := Field("id").InValues([]int64{1, 2, 3}).Build()
+	assert.Equal(t, "id IN (?, ?, ?)", s)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, args)
+	s, args = Field("id").NotInValues([2]string{"a", "b"}).Build()
+	assert.Equal(t, "id NOT IN (?, ?)", s)
+	assert.Equal(t, []interface{}{"a", "b"}, args)
+	assert.Equal(t, "id IN (?, a + 1)", partToString(Field("id").InValues([]interface{}{1, Field("a").Add(ValueInt(1))})))
+	assert.Panics(t, func() { Field("id").InValues(1) })
+}
+
+type testValuerList []string
+
+func (l testValuerList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+func TestPart_InSliceParam(t *testing.T) {
+	s, args := Field("id").In([]Part{Param([]int{4, 5})}).Build()
+	assert.Equal(t, "id IN (?, ?)", s)
+	assert.Equal(t, []interface{}{4, 5}, args)
+	s, args = Field("id").NotIn([]Part{Param([]string{"a"})}).Build()
+	assert.Equal(t, "id NOT IN (?)", s)
+	assert.Equal(t, []interface{}{"a"}, args)
+	s, args = Field("b").In([]Part{Param([]byte("ab"))}).Build()
+	assert.Equal(t, "b IN (?)", s)
+	assert.Equal(t, []interface{}{[]byte("ab")}, args)
+	s, args = Field("doc").In([]Part{Param(json.RawMessage("{}"))}).Build()
+	assert.Equal(t, "doc IN (?)", s)
+	assert.Equal(t, []interface{}{json.RawMessage("{}")}, args)
+	s, args = Field("h").In([]Part{Param([4]byte{1, 2, 3, 4})}).Build()
+	assert.Equal(t, "h IN (?)", s)
+	assert.Equal(t, []interface{}{[4]byte{1, 2, 3, 4}}, args)
+	s, _ = Field("v").In([]Part{Param(testValuerList{"a", "b"})}).Build()
+	assert.Equal(t, "v IN (?)", s)
+	assert.Equal(t, "id IN (NULL)", partToString(Field("id").In(nil)))
+	assert.Equal(t, "FALSE", partToString(Field("id").In([]Part{Param([]int{})})))
+}
+
+func TestPart_InValues_Empty(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t WHERE a = 1 AND FALSE", builderToString(NewQueryFrom(Table("t")).Select(All()).
+		Where(Field("a").Eq(ValueInt(1)), Field("id").InValues([]int{}))))
+	assert.Equal(t, "SELECT * FROM t WHERE TRUE", builderToString(NewQueryFrom(Table("t")).Select(All()).
+		Where(Field("id").NotInValues([]int{}, EmptyListFalse))))
+
+	q := NewQueryFrom(Table("t")).Select(All()).Where(Field("id").InValues([]int{}, EmptyListError))
+	_, _, err := q.TryBuild()
+	assert.ErrorIs(t, err, ErrEmptyList)
+	assert.EqualError(t, err, "empty list of values for IN")
+	assert.Panics(t, func() { q.Build() })
+	_, err = q.Compile()
+	assert.ErrorIs(t, err, ErrEmptyList)
+	assert.Equal(t, "/* empty list of values for IN */", fmt.Sprint(q))
+
+	assert.True(t, Field("id").InValues([]int{}, EmptyListSkip).IsZero())
+	skip := Field("id").NotInValues([]int{}, EmptyListSkip)
+	assert.Equal(t, "SELECT * FROM t WHERE (a = 1 OR b = 2)", builderToString(NewQueryFrom(Table("t")).Select(All()).
+		Where(skip, Field("a").Eq(ValueInt(1)).And(skip).Or(skip.Or(Field("b").Eq(ValueInt(2)))))))
+	assert.Equal(t, "SELECT * FROM t", builderToString(NewQueryFrom(Table("t")).Select(All()).Where(skip)))
+	assert.Equal(t, "SELECT * FROM t WHERE a = 1", builderToString(NewQueryFrom(Table("t")).Select(All()).
+		Where(Cond(skip), Not(skip), Not(Cond(skip)).Xor(Field("a").Eq(ValueInt(1))))))
+
+	_, _, err = NewQueryFrom(Table("t")).Select(All()).Where(Field("a").Eq(skip)).TryBuild()
+	assert.ErrorIs(t, err, ErrZeroPart)
+	_, _, err = NewQueryFrom(Table("t")).Select(If(skip, ValueInt(1), ValueInt(2))).TryBuild()
+	assert.ErrorIs(t, err, ErrZeroPart)
+	_, _, err = NewCase().When(skip, ValueInt(1)).Part().TryBuild()
+	assert.ErrorIs(t, err, ErrZeroPart)
+	assert.PanicsWithError(t, ErrZeroPart.Error(), func() { skip.Build() })
+}
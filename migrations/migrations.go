@@ -0,0 +1,381 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	qb "github.com/gvassili/query_builder"
+)
+
+var (
+	ErrLocked       = errors.New("migrations: lock is held by another runner")
+	ErrIrreversible = errors.New("migrations: migration has no down steps")
+)
+
+type Func func(ctx context.Context, tx *sql.Tx) error
+
+// Step is a single unit of a migration: either a built statement, or a Go
+// function for anything a statement can't express.
+type Step struct {
+	stmt qb.Statement
+	fn   Func
+}
+
+func Stmt(s qb.Statement) Step {
+	return Step{stmt: s}
+}
+
+func Fn(f Func) Step {
+	return Step{fn: f}
+}
+
+// Migration steps run in a transaction, along with the update of the version
+// table. MariaDB commits DDL statements implicitly though: a migration made of
+// several DDL steps is not atomic, and a failure leaves the steps before it
+// applied while the migration is still recorded as pending.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []Step
+	Down    []Step
+}
+
+type Migrator struct {
+	db          *sql.DB
+	table       string
+	lockName    string
+	lockTimeout time.Duration
+	dryRun      io.Writer
+	migrations  []Migration
+}
+
+func New(db *sql.DB) *Migrator {
+	return &Migrator{
+		db:          db,
+		table:       "schema_migrations",
+		lockName:    "schema_migrations",
+		lockTimeout: 10 * time.Second,
+	}
+}
+
+// Table changes the table tracking applied versions, which also names the
+// lock taken while migrating.
+func (m *Migrator) Table(name string) *Migrator {
+	m.table = name
+	m.lockName = name
+	return m
+}
+
+func (m *Migrator) LockTimeout(d time.Duration) *Migrator {
+	m.lockTimeout = d
+	return m
+}
+
+// DryRun makes the migrator print the statements it would run to w instead
+// of running them. Only the reads needed to find pending migrations hit the
+// database.
+func (m *Migrator) DryRun(w io.Writer) *Migrator {
+	m.dryRun = w
+	return m
+}
+
+func (m *Migrator) Register(ms ...Migration) error {
+	for _, v := range ms {
+		if v.Version <= 0 {
+			return fmt.Errorf("migrations: invalid version %d for %q", v.Version, v.Name)
+		}
+		for _, r := range m.migrations {
+			if r.Version == v.Version {
+				return fmt.Errorf("migrations: version %d registered twice, by %q and %q", v.Version, r.Name, v.Name)
+			}
+		}
+		for _, steps := range [][]Step{v.Up, v.Down} {
+			for i, step := range steps {
+				if (step.stmt == nil) == (step.fn == nil) {
+					return fmt.Errorf("migrations: step %d of %d %q has no statement nor function", i, v.Version, v.Name)
+				}
+			}
+		}
+		m.migrations = append(m.migrations, v)
+	}
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return nil
+}
+
+// Applied returns the applied versions in ascending order.
+func (m *Migrator) Applied(ctx context.Context) ([]int64, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if exists, err := m.tableExists(ctx, conn); err != nil || !exists {
+		return nil, err
+	}
+	return m.applied(ctx, conn)
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.UpTo(ctx, 0)
+}
+
+// UpTo applies the pending migrations up to version included, 0 meaning all
+// of them.
+func (m *Migrator) UpTo(ctx context.Context, version int64) error {
+	return m.run(ctx, func(applied map[int64]bool) []Migration {
+		var plan []Migration
+		for _, v := range m.migrations {
+			if version != 0 && v.Version > version {
+				break
+			}
+			if !applied[v.Version] {
+				plan = append(plan, v)
+			}
+		}
+		return plan
+	}, true)
+}
+
+// Down rolls back the last applied migration. Migrations without down steps
+// can't be rolled back and fail with ErrIrreversible.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.run(ctx, func(applied map[int64]bool) []Migration {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if applied[m.migrations[i].Version] {
+				return m.migrations[i : i+1]
+			}
+		}
+		return nil
+	}, false)
+}
+
+// DownTo rolls back every applied migration above version, newest first.
+// Nothing is rolled back if one of them has no down steps.
+func (m *Migrator) DownTo(ctx context.Context, version int64) error {
+	return m.run(ctx, func(applied map[int64]bool) []Migration {
+		var plan []Migration
+		for i := len(m.migrations) - 1; i >= 0 && m.migrations[i].Version > version; i-- {
+			if applied[m.migrations[i].Version] {
+				plan = append(plan, m.migrations[i])
+			}
+		}
+		return plan
+	}, false)
+}
+
+func (m *Migrator) run(ctx context.Context, planner func(applied map[int64]bool) []Migration, up bool) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if m.dryRun == nil {
+		if err := m.lock(ctx, conn); err != nil {
+			return err
+		}
+		defer func() {
+			if _, rerr := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", m.lockName); rerr != nil && err == nil {
+				err = rerr
+			}
+		}()
+	}
+	exists, err := m.ensureTable(ctx, conn)
+	if err != nil {
+		return err
+	}
+	applied := map[int64]bool{}
+	if exists {
+		versions, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			applied[v] = true
+		}
+	}
+	plan := planner(applied)
+	if !up {
+		for _, v := range plan {
+			if len(v.Down) == 0 {
+				return fmt.Errorf("%w: %d %s", ErrIrreversible, v.Version, v.Name)
+			}
+		}
+	}
+	for _, v := range plan {
+		if err := m.migrate(ctx, conn, v, up); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) error {
+	var ok sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", m.lockName, int64(m.lockTimeout/time.Second)).Scan(&ok); err != nil {
+		return err
+	}
+	if !ok.Valid {
+		return fmt.Errorf("migrations: could not take lock %q", m.lockName)
+	}
+	if ok.Int64 != 1 {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, conn *sql.Conn) (bool, error) {
+	s, args := qb.NewQueryFrom(qb.Table("information_schema.tables")).
+		Select(qb.Count(qb.All())).
+		Where(qb.Field("table_schema").Eq(qb.Func("DATABASE")), qb.Field("table_name").Eq(qb.ParamString(m.table))).
+		Build()
+	var n int
+	err := conn.QueryRowContext(ctx, s, args...).Scan(&n)
+	return n != 0, err
+}
+
+// ensureTable creates the version table if needed, and reports whether it
+// exists afterwards, which is not the case during a dry run.
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) (bool, error) {
+	if exists, err := m.tableExists(ctx, conn); err != nil || exists {
+		return exists, err
+	}
+	err := m.exec(ctx, conn, qb.NewCreateTable(m.table).IfNotExists().
+		Column(
+			qb.NewColumn("version", qb.TypeBigInt()).NotNull().PrimaryKey(),
+			qb.NewColumn("name", qb.TypeVarchar(255)).NotNull(),
+			qb.NewColumn("applied_at", qb.TypeTimestamp(0)).NotNull().Default(qb.Func("CURRENT_TIMESTAMP")),
+		))
+	return err == nil && m.dryRun == nil, err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) ([]int64, error) {
+	s, args := qb.NewQueryFrom(qb.Table(m.table)).
+		Select(qb.Field("version")).
+		OrderBy(qb.Field("version"), qb.OrderDirectionAsc).
+		Build()
+	rows, err := conn.QueryContext(ctx, s, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var versions []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+type tryBuilder interface {
+	TryBuild() (string, []interface{}, error)
+}
+
+type contextBuilder interface {
+	BuildContext(ctx context.Context) (string, []interface{}, error)
+}
+
+// build builds s as the query_builder Executor does, so that a statement
+// failing to build returns an error rather than panicking.
+func build(ctx context.Context, s qb.Statement) (string, []interface{}, error) {
+	switch b := s.(type) {
+	case contextBuilder:
+		return b.BuildContext(ctx)
+	case tryBuilder:
+		return b.TryBuild()
+	}
+	q, args := s.Build()
+	return q, args, nil
+}
+
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, s qb.Statement) error {
+	q, args, err := build(ctx, s)
+	if err != nil {
+		return err
+	}
+	if m.dryRun != nil {
+		return m.print(q, args)
+	}
+	_, err = conn.ExecContext(ctx, q, args...)
+	return err
+}
+
+func (m *Migrator) print(q string, args []interface{}) error {
+	if len(args) != 0 {
+		_, err := fmt.Fprintf(m.dryRun, "%s; -- %v\n", q, args)
+		return err
+	}
+	_, err := fmt.Fprintf(m.dryRun, "%s;\n", q)
+	return err
+}
+
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, v Migration, up bool) error {
+	steps, direction := v.Up, "up"
+	if !up {
+		steps, direction = v.Down, "down"
+	}
+	if m.dryRun != nil {
+		if _, err := fmt.Fprintf(m.dryRun, "-- %d %s (%s)\n", v.Version, v.Name, direction); err != nil {
+			return err
+		}
+		for _, step := range steps {
+			var err error
+			if step.stmt != nil {
+				q, args, berr := build(ctx, step.stmt)
+				if berr != nil {
+					return fmt.Errorf("migrations: %d %s (%s): %w", v.Version, v.Name, direction, berr)
+				}
+				err = m.print(q, args)
+			} else {
+				_, err = fmt.Fprintln(m.dryRun, "-- go function")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if up {
+			return m.print("INSERT INTO "+m.table+" (version, name) VALUES (?, ?)", []interface{}{v.Version, v.Name})
+		}
+		return m.print("DELETE FROM "+m.table+" WHERE version = ?", []interface{}{v.Version})
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if step.stmt != nil {
+			var s string
+			var args []interface{}
+			if s, args, err = build(ctx, step.stmt); err == nil {
+				_, err = tx.ExecContext(ctx, s, args...)
+			}
+		} else {
+			err = step.fn(ctx, tx)
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrations: %d %s (%s): %w", v.Version, v.Name, direction, err)
+		}
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO "+m.table+" (version, name) VALUES (?, ?)", v.Version, v.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM "+m.table+" WHERE version = ?", v.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
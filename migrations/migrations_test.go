@@ -0,0 +1,360 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	qb "github.com/gvassili/query_builder"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB emulates just enough of MariaDB for the migrator: the lock functions,
+// the information_schema lookup and the version table.
+type fakeDB struct {
+	mu       sync.Mutex
+	locked   bool
+	exists   bool
+	versions map[int64]bool
+	execs    []string
+	failOn   string
+}
+
+var fakeDBs sync.Map
+
+func init() {
+	sql.Register("migrations_fake", fakeDriver{})
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDB) {
+	f := &fakeDB{versions: map[int64]bool{}}
+	fakeDBs.Store(t.Name(), f)
+	db, err := sql.Open("migrations_fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	f, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", name)
+	}
+	return &fakeConn{db: f.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c.db, query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	f := s.db
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failOn != "" && strings.Contains(s.query, f.failOn) {
+		return nil, errors.New("exec failed")
+	}
+	switch {
+	case strings.HasPrefix(s.query, "SELECT RELEASE_LOCK"):
+		f.locked = false
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+		f.exists = true
+	case strings.HasPrefix(s.query, "INSERT INTO schema_migrations"):
+		f.versions[args[0].(int64)] = true
+	case strings.HasPrefix(s.query, "DELETE FROM schema_migrations"):
+		delete(f.versions, args[0].(int64))
+	}
+	f.execs = append(f.execs, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	f := s.db
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "SELECT GET_LOCK"):
+		if f.locked {
+			return &fakeRows{values: [][]driver.Value{{int64(0)}}}, nil
+		}
+		f.locked = true
+		return &fakeRows{values: [][]driver.Value{{int64(1)}}}, nil
+	case strings.HasPrefix(s.query, "SELECT COUNT(*) FROM information_schema.tables"):
+		n := int64(0)
+		if f.exists {
+			n = 1
+		}
+		return &fakeRows{values: [][]driver.Value{{n}}}, nil
+	case strings.HasPrefix(s.query, "SELECT version FROM schema_migrations"):
+		if !f.exists {
+			return nil, errors.New("table doesn't exist")
+		}
+		var vs []int64
+		for v := range f.versions {
+			vs = append(vs, v)
+		}
+		sort.Slice(vs, func(i, j int) bool { return vs[i] < vs[j] })
+		rows := &fakeRows{}
+		for _, v := range vs {
+			rows.values = append(rows.values, []driver.Value{v})
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", s.query)
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"v"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 2,
+			Name:    "add email",
+			Up:      []Step{Stmt(qb.NewAlterTable("users").AddColumn(qb.NewColumn("email", qb.TypeVarchar(255))))},
+			Down:    []Step{Stmt(qb.NewAlterTable("users").DropColumn("email"))},
+		},
+		{
+			Version: 1,
+			Name:    "create users",
+			Up:      []Step{Stmt(qb.NewCreateTable("users").Column(qb.NewColumn("id", qb.TypeInt()).PrimaryKey()))},
+			Down:    []Step{Stmt(qb.NewDropTable("users"))},
+		},
+		{
+			Version: 3,
+			Name:    "backfill",
+			Up: []Step{Fn(func(ctx context.Context, tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "UPDATE users SET email = ''")
+				return err
+			})},
+		},
+	}
+}
+
+func TestMigrator_Register(t *testing.T) {
+	m := New(nil)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.Equal(t, []int64{1, 2, 3}, []int64{m.migrations[0].Version, m.migrations[1].Version, m.migrations[2].Version})
+	assert.Error(t, m.Register(Migration{Version: 2, Name: "duplicate"}))
+	assert.Error(t, m.Register(Migration{Version: 0, Name: "zero"}))
+	assert.EqualError(t, m.Register(Migration{Version: 4, Name: "empty", Up: []Step{{}}}), `migrations: step 0 of 4 "empty" has no statement nor function`)
+	assert.Error(t, m.Register(Migration{Version: 5, Name: "nil", Down: []Step{Stmt(nil)}}))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.NoError(t, m.Up(context.Background()))
+	assert.Equal(t, []string{
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP())",
+		"CREATE TABLE users (id INT PRIMARY KEY)",
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		"ALTER TABLE users ADD COLUMN email VARCHAR(255)",
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+		"UPDATE users SET email = ''",
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+	}, f.execs)
+	assert.False(t, f.locked)
+
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, applied)
+
+	f.execs = nil
+	assert.NoError(t, m.Up(context.Background()))
+	assert.Empty(t, f.execs)
+}
+
+func TestMigrator_UpTo(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.NoError(t, m.UpTo(context.Background(), 1))
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, applied)
+	assert.Len(t, f.execs, 3)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()[:2]...))
+	assert.NoError(t, m.Up(context.Background()))
+	f.execs = nil
+	assert.NoError(t, m.Down(context.Background()))
+	assert.Equal(t, []string{
+		"ALTER TABLE users DROP COLUMN email",
+		"DELETE FROM schema_migrations WHERE version = ?",
+	}, f.execs)
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, applied)
+}
+
+func TestMigrator_DownTo(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()[:2]...))
+	assert.NoError(t, m.Up(context.Background()))
+	f.execs = nil
+	assert.NoError(t, m.DownTo(context.Background(), 0))
+	assert.Equal(t, []string{
+		"ALTER TABLE users DROP COLUMN email",
+		"DELETE FROM schema_migrations WHERE version = ?",
+		"DROP TABLE users",
+		"DELETE FROM schema_migrations WHERE version = ?",
+	}, f.execs)
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestMigrator_Irreversible(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.NoError(t, m.Up(context.Background()))
+	f.execs = nil
+	err := m.DownTo(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrIrreversible)
+	assert.EqualError(t, err, "migrations: migration has no down steps: 3 backfill")
+	assert.ErrorIs(t, m.Down(context.Background()), ErrIrreversible)
+	assert.Empty(t, f.execs)
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, applied)
+}
+
+func TestMigrator_Failure(t *testing.T) {
+	db, f := openFake(t)
+	f.failOn = "ALTER TABLE"
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()...))
+	err := m.Up(context.Background())
+	assert.EqualError(t, err, "migrations: 2 add email (up): exec failed")
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, applied)
+	assert.False(t, f.locked)
+}
+
+func TestMigrator_Locked(t *testing.T) {
+	db, f := openFake(t)
+	f.locked = true
+	m := New(db)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.ErrorIs(t, m.Up(context.Background()), ErrLocked)
+	assert.Empty(t, f.execs)
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	db, f := openFake(t)
+	var out bytes.Buffer
+	m := New(db).DryRun(&out)
+	assert.NoError(t, m.Register(testMigrations()...))
+	assert.NoError(t, m.Up(context.Background()))
+	assert.Empty(t, f.execs)
+	assert.False(t, f.exists)
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP());
+-- 1 create users (up)
+CREATE TABLE users (id INT PRIMARY KEY);
+INSERT INTO schema_migrations (version, name) VALUES (?, ?); -- [1 create users]
+-- 2 add email (up)
+ALTER TABLE users ADD COLUMN email VARCHAR(255);
+INSERT INTO schema_migrations (version, name) VALUES (?, ?); -- [2 add email]
+-- 3 backfill (up)
+-- go function
+INSERT INTO schema_migrations (version, name) VALUES (?, ?); -- [3 backfill]
+`, out.String())
+}
+
+func TestMigrator_BuildError(t *testing.T) {
+	db, f := openFake(t)
+	m := New(db)
+	bad := Migration{
+		Version: 4,
+		Name:    "bad",
+		Up:      []Step{Stmt(qb.NewDelete(qb.Table("users")).Where(qb.Field("id").InValues([]int{}, qb.EmptyListError)))},
+	}
+	assert.NoError(t, m.Register(append(testMigrations(), bad)...))
+	err := m.Up(context.Background())
+	assert.ErrorIs(t, err, qb.ErrEmptyList)
+	assert.EqualError(t, err, "migrations: 4 bad (up): empty list of values for IN")
+	applied, err := m.Applied(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, applied)
+	assert.False(t, f.locked)
+
+	var out bytes.Buffer
+	m = New(db).DryRun(&out)
+	assert.NoError(t, m.Register(bad))
+	assert.ErrorIs(t, m.Up(context.Background()), qb.ErrEmptyList)
+}
@@ -9,35 +9,30 @@ import (
 
 type partByte byte
 
-func (b partByte) Build() (string, []interface{}) {
-	return string(b), nil
+func (b partByte) BuildTo(w *Writer) {
+	w.WriteByte(byte(b))
 }
 
 type partString string
 
-func (t partString) Build() (string, []interface{}) {
-	return string(t), nil
+func (t partString) BuildTo(w *Writer) {
+	w.WriteString(string(t))
 }
 
 type partParam struct {
 	v interface{}
 }
 
-func (a partParam) Build() (string, []interface{}) {
-	return "?", []interface{}{a.v}
+func (a partParam) BuildTo(w *Writer) {
+	w.WriteParam(a.v)
 }
 
 type parts []builder
 
-func (ps parts) Build() (string, []interface{}) {
-	var sb strings.Builder
-	var vs []interface{}
+func (ps parts) BuildTo(w *Writer) {
 	for _, p := range ps {
-		s, v := p.Build()
-		sb.WriteString(s)
-		vs = append(vs, v...)
+		p.BuildTo(w)
 	}
-	return sb.String(), vs
 }
 
 type Part struct {
@@ -48,6 +43,16 @@ func (p Part) IsZero() bool {
 	return p.builder == nil
 }
 
+// BuildTo fails the build with ErrZeroPart when p is zero: only Where, And,
+// Or, Xor, Not and Cond know to drop zero parts, such as skipped predicates.
+func (p Part) BuildTo(w *Writer) {
+	if p.builder == nil {
+		w.Fail(ErrZeroPart)
+		return
+	}
+	p.builder.BuildTo(w)
+}
+
 func (p Part) append(vs ...builder) Part {
 	if ps, ok := p.builder.(parts); ok {
 		return Part{append(ps, vs...)}
@@ -57,91 +62,266 @@ func (p Part) append(vs ...builder) Part {
 }
 
 func (p Part) As(v string) Part {
+	if t, ok := p.builder.(partTable); ok && t.alias == "" {
+		t.alias = v
+		return Part{t}
+	}
 	return p.append(partString(" AS " + v))
 }
 
 func (p Part) Eq(v Part) Part {
-	return p.append(partString(" = "), v)
+	return Part{binary(p, "=", precCompare, v)}
 }
 
 func (p Part) Ne(v Part) Part {
-	return p.append(partString(" != "), v)
+	return Part{binary(p, "!=", precCompare, v)}
+}
+
+func (p Part) NullSafeEq(v Part) Part {
+	return Part{binary(p, "<=>", precCompare, v)}
 }
 
 func (p Part) Lt(v Part) Part {
-	return p.append(partString(" < "), v)
+	return Part{binary(p, "<", precCompare, v)}
 }
 
 func (p Part) Lte(v Part) Part {
-	return p.append(partString(" <= "), v)
+	return Part{binary(p, "<=", precCompare, v)}
 }
 
 func (p Part) Gt(v Part) Part {
-	return p.append(partString(" > "), v)
+	return Part{binary(p, ">", precCompare, v)}
 }
 
 func (p Part) Gte(v Part) Part {
-	return p.append(partString(" >= "), v)
+	return Part{binary(p, ">=", precCompare, v)}
 }
 
-func (p Part) In(vs []Part) Part {
+func (p Part) in(op string, vs []Part) Part {
+	e := partExpr{op, precCompare, parts{operand(p, precedenceOf(p) < precCompare)}}
 	if len(vs) == 0 {
-		p = p.append(partString(" IN (NULL)"))
+		e.parts = append(e.parts, partString(" "+op+" (NULL)"))
 	} else {
-		p = p.append(partString(" IN ("))
+		e.parts = append(e.parts, partString(" "+op+" ("))
 		for i, v := range vs {
 			if i != 0 {
-				p = p.append(partString(", "))
+				e.parts = append(e.parts, partString(", "))
 			}
-			p = p.append(v)
+			e.parts = append(e.parts, v)
 		}
-		p = p.append(partByte(')'))
+		e.parts = append(e.parts, partByte(')'))
 	}
-	return p
+	return Part{e}
+}
+
+// In renders p IN (vs...). A single slice param is expanded as InValues does.
+func (p Part) In(vs []Part) Part {
+	if ps, ok := sliceParam(vs); ok {
+		return p.inValues("IN", ps, nil)
+	}
+	return p.in("IN", vs)
 }
 
 func (p Part) NotIn(vs []Part) Part {
-	return p.append(partString(" NOT")).In(vs)
+	if ps, ok := sliceParam(vs); ok {
+		return p.inValues("NOT IN", ps, nil)
+	}
+	return p.in("NOT IN", vs)
+}
+
+func (p Part) Like(v Part) Part {
+	return Part{binary(p, "LIKE", precCompare, v)}
+}
+
+func (p Part) NotLike(v Part) Part {
+	return Part{binary(p, "NOT LIKE", precCompare, v)}
+}
+
+func (p Part) LikeEscape(v Part, escape byte) Part {
+	e := binary(p, "LIKE", precCompare, v)
+	e.parts = append(e.parts, likeEscape(escape))
+	return Part{e}
+}
+
+func (p Part) NotLikeEscape(v Part, escape byte) Part {
+	e := binary(p, "NOT LIKE", precCompare, v)
+	e.parts = append(e.parts, likeEscape(escape))
+	return Part{e}
+}
+
+func likeEscape(escape byte) partString {
+	if escape == '\\' || escape == '\'' {
+		return partString(" ESCAPE '\\" + string(escape) + "'")
+	}
+	return partString(" ESCAPE '" + string(escape) + "'")
+}
+
+// likeEscapeChar is the escape character used by Contains, StartsWith and
+// EndsWith, picked so that it never needs quoting inside the ESCAPE literal.
+const likeEscapeChar = '!'
+
+// EscapeLike escapes the LIKE wildcards and the escape character itself in s,
+// so that it matches literally in a pattern using escape as ESCAPE character.
+func EscapeLike(s string, escape byte) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '%' || c == '_' || c == escape {
+			sb.WriteByte(escape)
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func (p Part) Contains(s string) Part {
+	return p.LikeEscape(ParamString("%"+EscapeLike(s, likeEscapeChar)+"%"), likeEscapeChar)
+}
+
+func (p Part) StartsWith(s string) Part {
+	return p.LikeEscape(ParamString(EscapeLike(s, likeEscapeChar)+"%"), likeEscapeChar)
+}
+
+func (p Part) EndsWith(s string) Part {
+	return p.LikeEscape(ParamString("%"+EscapeLike(s, likeEscapeChar)), likeEscapeChar)
+}
+
+func (p Part) Regexp(v Part) Part {
+	return Part{binary(p, "REGEXP", precCompare, v)}
+}
+
+func (p Part) NotRegexp(v Part) Part {
+	return Part{binary(p, "NOT REGEXP", precCompare, v)}
+}
+
+func (p Part) RLike(v Part) Part {
+	return Part{binary(p, "RLIKE", precCompare, v)}
+}
+
+func (p Part) between(op string, lo, hi Part) Part {
+	return Part{partExpr{op, precBetween, parts{
+		operand(p, precedenceOf(p) <= precBetween),
+		partString(" " + op + " "),
+		operand(lo, precedenceOf(lo) <= precBetween),
+		partString(" AND "),
+		operand(hi, precedenceOf(hi) <= precBetween),
+	}}}
+}
+
+func (p Part) Between(lo, hi Part) Part {
+	return p.between("BETWEEN", lo, hi)
+}
+
+func (p Part) NotBetween(lo, hi Part) Part {
+	return p.between("NOT BETWEEN", lo, hi)
 }
 
 func (p Part) Add(v Part) Part {
-	return p.append(partString(" + "), v)
+	return Part{binary(p, "+", precAdd, v)}
 }
 
 func (p Part) Sub(v Part) Part {
-	return p.append(partString(" - "), v)
+	return Part{binary(p, "-", precAdd, v)}
+}
+
+func (p Part) Mul(v Part) Part {
+	return Part{binary(p, "*", precMul, v)}
+}
+
+func (p Part) Div(v Part) Part {
+	return Part{binary(p, "/", precMul, v)}
+}
+
+func (p Part) IntDiv(v Part) Part {
+	return Part{binary(p, "DIV", precMul, v)}
+}
+
+func (p Part) Mod(v Part) Part {
+	return Part{binary(p, "MOD", precMul, v)}
+}
+
+func (p Part) BitAnd(v Part) Part {
+	return Part{binary(p, "&", precBitAnd, v)}
+}
+
+func (p Part) BitOr(v Part) Part {
+	return Part{binary(p, "|", precBitOr, v)}
+}
+
+func (p Part) BitXor(v Part) Part {
+	return Part{binary(p, "^", precBitXor, v)}
+}
+
+func (p Part) ShiftLeft(v Part) Part {
+	return Part{binary(p, "<<", precShift, v)}
+}
+
+func (p Part) ShiftRight(v Part) Part {
+	return Part{binary(p, ">>", precShift, v)}
+}
+
+func Neg(v Part) Part {
+	return Part{unary("-", precUnary, v)}
+}
+
+func BitNot(v Part) Part {
+	return Part{unary("~", precUnary, v)}
+}
+
+func Not(v Part) Part {
+	if v.IsZero() {
+		return v
+	}
+	return Part{unary("NOT ", precNot, v)}
 }
 
 func (p Part) Is(v Part) Part {
-	return p.append(partString(" IS "), v)
+	return Part{binary(p, "IS", precCompare, v)}
 }
 
 func (p Part) IsNot(v Part) Part {
-	return p.append(partString(" IS NOT "), v)
+	return Part{binary(p, "IS NOT", precCompare, v)}
 }
 
 func (p Part) And(v Part) Part {
-	return p.append(partString(" AND "), v)
+	return And(p, v)
 }
 
+// And, Or and Xor return the other operand when one of them is zero, as
+// skipped predicates are.
 func And(l, r Part) Part {
-	return Part{parts{l, partString(" AND "), r}}
+	if l.IsZero() {
+		return r
+	} else if r.IsZero() {
+		return l
+	}
+	return Part{binary(l, "AND", precAnd, r)}
 }
 
 func (p Part) Or(v Part) Part {
-	return p.append(partString(" OR "), v)
+	return Or(p, v)
 }
 
 func Or(l, r Part) Part {
-	return Part{parts{l, partString(" OR "), r}}
+	if l.IsZero() {
+		return r
+	} else if r.IsZero() {
+		return l
+	}
+	return Part{binary(l, "OR", precOr, r)}
 }
 
 func (p Part) Xor(v Part) Part {
-	return p.append(partString(" XOR "), v)
+	return Xor(p, v)
 }
 
 func Xor(l, r Part) Part {
-	return Part{parts{l, partString(" XOR "), r}}
+	if l.IsZero() {
+		return r
+	} else if r.IsZero() {
+		return l
+	}
+	return Part{binary(l, "XOR", precXor, r)}
 }
 
 func List(vs ...Part) Part {
@@ -156,27 +336,38 @@ func List(vs ...Part) Part {
 }
 
 func Cond(v Part) Part {
+	if v.IsZero() {
+		return v
+	}
 	return Part{parts{partByte('('), v, partByte(')')}}
 }
 
 func Min(v Part) Part {
-	return Part{parts{partString("MIN("), v, partByte(')')}}
+	return Func("MIN", v)
 }
 
 func Max(v Part) Part {
-	return Part{parts{partString("MAX("), v, partByte(')')}}
+	return Func("MAX", v)
 }
 
 func Count(v Part) Part {
-	return Part{parts{partString("COUNT("), v, partByte(')')}}
+	return Func("COUNT", v)
+}
+
+func Avg(v Part) Part {
+	return Func("AVG", v)
 }
 
 func Average(v Part) Part {
-	return Part{parts{partString("CAST(AVG("), v, partString(") AS DECIMAL(7,2))")}}
+	return AverageDecimal(v, 7, 2)
+}
+
+func AverageDecimal(v Part, precision, scale int) Part {
+	return Cast(Avg(v), TypeDecimal(precision, scale))
 }
 
 func ToBase64(v Part) Part {
-	return Part{parts{partString("TO_BASE64("), v, partByte(')')}}
+	return Func("TO_BASE64", v)
 }
 
 func DateOverlaps(startA Part, endA Part, startB time.Time, endB time.Time) Part {
@@ -192,16 +383,7 @@ func DateOverlaps(startA Part, endA Part, startB time.Time, endB time.Time) Part
 }
 
 func Concat(vs ...Part) Part {
-	p := make(parts, 0, 2+(len(vs)<<1))
-	p = append(p, partString("CONCAT("))
-	for i, v := range vs {
-		if i != 0 {
-			p = append(p, partString(", "))
-		}
-		p = append(p, v)
-	}
-	p = append(p, partByte(')'))
-	return Part{p}
+	return Func("CONCAT", vs...)
 }
 
 func Distinct(v Part) Part {
@@ -209,15 +391,71 @@ func Distinct(v Part) Part {
 }
 
 func JsonExtract(v, cmd Part) Part {
-	return Part{parts{partString("JSON_EXTRACT("), v, partString(", "), cmd, partByte(')')}}
+	return Func("JSON_EXTRACT", v, cmd)
 }
 
 func If(cond, def, v Part) Part {
-	return Part{parts{partString("IF("), cond, partString(", "), def, partString(", "), v, partByte(')')}}
+	return Func("IF", cond, def, v)
 }
 
 func Case(cond, then, els Part) Part {
-	return Part{parts{partString("CASE WHEN "), cond, partString(" THEN "), then, partString(" ELSE "), els, partString(" END")}}
+	return NewCase().When(cond, then).Else(els).Part()
+}
+
+type CaseBuilder struct {
+	value Part
+	whens parts
+	els   Part
+}
+
+func NewCase() *CaseBuilder {
+	return &CaseBuilder{}
+}
+
+// NewCaseValue starts a simple CASE, whose When conditions are the values
+// compared to v.
+func NewCaseValue(v Part) *CaseBuilder {
+	return &CaseBuilder{value: v}
+}
+
+func (cb *CaseBuilder) When(cond, then Part) *CaseBuilder {
+	cb.whens = append(cb.whens, partString(" WHEN "), cond, partString(" THEN "), then)
+	return cb
+}
+
+func (cb *CaseBuilder) Else(v Part) *CaseBuilder {
+	cb.els = v
+	return cb
+}
+
+// Part fails the build with ErrCaseNoWhen when no When was given.
+func (cb *CaseBuilder) Part() Part {
+	if len(cb.whens) == 0 {
+		return Part{partError{ErrCaseNoWhen}}
+	}
+	p := make(parts, 0, len(cb.whens)+5)
+	p = append(p, partString("CASE"))
+	if !cb.value.IsZero() {
+		p = append(p, partByte(' '), cb.value)
+	}
+	p = append(p, cb.whens...)
+	if !cb.els.IsZero() {
+		p = append(p, partString(" ELSE "), cb.els)
+	}
+	p = append(p, partString(" END"))
+	return Part{p}
+}
+
+func (cb *CaseBuilder) BuildTo(w *Writer) {
+	cb.Part().BuildTo(w)
+}
+
+func (cb *CaseBuilder) Build() (string, []interface{}) {
+	return build(cb)
+}
+
+func (cb *CaseBuilder) TryBuild() (string, []interface{}, error) {
+	return tryBuild(cb)
 }
 
 func Exists(query *Query) Part {
@@ -267,6 +505,33 @@ func ValueString(v string) Part {
 	return Part{partString("'" + v + "'")}
 }
 
+// quoteString renders s as a MariaDB string literal, assuming the
+// NO_BACKSLASH_ESCAPES sql mode is not set.
+func quoteString(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			sb.WriteString(`\0`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case 0x1a:
+			sb.WriteString(`\Z`)
+		case '\'', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
 func ValueInt(v int) Part {
 	return Part{partString(strconv.Itoa(v))}
 }
@@ -283,8 +548,13 @@ func ValueBool(v bool) Part {
 	}
 }
 
+// Table is a table, name possibly qualified by its schema. It fails the
+// build when name holds an alias too: use As.
 func Table(name string) Part {
-	return Part{partString(name)}
+	if strings.ContainsAny(name, " \t\r\n") {
+		return Part{partError{fmt.Errorf("%w: %q", ErrTableName, name)}}
+	}
+	return Part{partTable{name: name}}
 }
 
 func Field(field string) Part {
@@ -368,15 +638,23 @@ func (vb *ValueBuilder) Append(values ...Part) {
 }
 
 func (p Part) Build() (string, []interface{}) {
-	return p.builder.Build()
+	return build(p)
+}
+
+func (p Part) TryBuild() (string, []interface{}, error) {
+	return tryBuild(p)
 }
 
 func (vb ValueBuilder) Part() Part {
 	return Part{vb}
 }
 
+func (vb ValueBuilder) BuildTo(w *Writer) {
+	(parts)(vb).BuildTo(w)
+}
+
 func (vb ValueBuilder) Build() (string, []interface{}) {
-	return (parts)(vb).Build()
+	return build(vb)
 }
 
 func TableFields(name string, fields ...Part) Part {
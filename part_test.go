@@ -56,6 +56,51 @@ func TestPart_NotIn(t *testing.T) {
 	assert.Equal(t, `field_name NOT IN (1, 2, 3)`, partToString(Field("field_name").NotIn([]Part{ValueInt(1), ValueInt(2), ValueInt(3)})))
 }
 
+func TestPart_Like(t *testing.T) {
+	assert.Equal(t, `field_name LIKE 'a%'`, partToString(Field("field_name").Like(ValueString("a%"))))
+	assert.Equal(t, `field_name NOT LIKE 'a%'`, partToString(Field("field_name").NotLike(ValueString("a%"))))
+}
+
+func TestPart_LikeEscape(t *testing.T) {
+	assert.Equal(t, `field_name LIKE ? ESCAPE '#'`, partToString(Field("field_name").LikeEscape(ParamString("#%"), '#')))
+	assert.Equal(t, `field_name NOT LIKE ? ESCAPE '#'`, partToString(Field("field_name").NotLikeEscape(ParamString("#%"), '#')))
+	assert.Equal(t, `field_name LIKE ? ESCAPE '\\'`, partToString(Field("field_name").LikeEscape(ParamString("\\%"), '\\')))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `50!% off!_now!!`, EscapeLike("50% off_now!", '!'))
+	assert.Equal(t, `a\\b\%`, EscapeLike(`a\b%`, '\\'))
+}
+
+func TestPart_Contains(t *testing.T) {
+	s, v := Field("field_name").Contains("50%_off").Build()
+	assert.Equal(t, `field_name LIKE ? ESCAPE '!'`, s)
+	assert.Equal(t, []interface{}{"%50!%!_off%"}, v)
+}
+
+func TestPart_StartsWith(t *testing.T) {
+	s, v := Field("field_name").StartsWith("a_b").Build()
+	assert.Equal(t, `field_name LIKE ? ESCAPE '!'`, s)
+	assert.Equal(t, []interface{}{"a!_b%"}, v)
+}
+
+func TestPart_EndsWith(t *testing.T) {
+	s, v := Field("field_name").EndsWith("a!b").Build()
+	assert.Equal(t, `field_name LIKE ? ESCAPE '!'`, s)
+	assert.Equal(t, []interface{}{"%a!!b"}, v)
+}
+
+func TestPart_Regexp(t *testing.T) {
+	assert.Equal(t, `field_name REGEXP '^a'`, partToString(Field("field_name").Regexp(ValueString("^a"))))
+	assert.Equal(t, `field_name NOT REGEXP '^a'`, partToString(Field("field_name").NotRegexp(ValueString("^a"))))
+	assert.Equal(t, `field_name RLIKE '^a'`, partToString(Field("field_name").RLike(ValueString("^a"))))
+}
+
+func TestPart_Between(t *testing.T) {
+	assert.Equal(t, `field_name BETWEEN 1 AND 10`, partToString(Field("field_name").Between(ValueInt(1), ValueInt(10))))
+	assert.Equal(t, `field_name NOT BETWEEN 1 AND 10`, partToString(Field("field_name").NotBetween(ValueInt(1), ValueInt(10))))
+}
+
 func TestPart_Add(t *testing.T) {
 	assert.Equal(t, `field_name + 123`, partToString(Field("field_name").Add(ValueInt(123))))
 }
@@ -64,6 +109,57 @@ func TestPart_Sub(t *testing.T) {
 	assert.Equal(t, `field_name - 123`, partToString(Field("field_name").Sub(ValueInt(123))))
 }
 
+func TestPart_Mul(t *testing.T) {
+	assert.Equal(t, `field_name * 2`, partToString(Field("field_name").Mul(ValueInt(2))))
+	assert.Equal(t, `field_name / 2`, partToString(Field("field_name").Div(ValueInt(2))))
+	assert.Equal(t, `field_name DIV 2`, partToString(Field("field_name").IntDiv(ValueInt(2))))
+	assert.Equal(t, `field_name MOD 2`, partToString(Field("field_name").Mod(ValueInt(2))))
+}
+
+func TestPart_Bitwise(t *testing.T) {
+	assert.Equal(t, `field_name & 4`, partToString(Field("field_name").BitAnd(ValueInt(4))))
+	assert.Equal(t, `field_name | 4`, partToString(Field("field_name").BitOr(ValueInt(4))))
+	assert.Equal(t, `field_name ^ 4`, partToString(Field("field_name").BitXor(ValueInt(4))))
+	assert.Equal(t, `field_name << 2`, partToString(Field("field_name").ShiftLeft(ValueInt(2))))
+	assert.Equal(t, `field_name >> 2`, partToString(Field("field_name").ShiftRight(ValueInt(2))))
+	assert.Equal(t, `~field_name`, partToString(BitNot(Field("field_name"))))
+}
+
+func TestNeg(t *testing.T) {
+	assert.Equal(t, `-field_name`, partToString(Neg(Field("field_name"))))
+	assert.Equal(t, `-(field_name + 1)`, partToString(Neg(Field("field_name").Add(ValueInt(1)))))
+	assert.Equal(t, `-(-field_name)`, partToString(Neg(Neg(Field("field_name")))))
+}
+
+func TestNot(t *testing.T) {
+	assert.Equal(t, `NOT field_name = 1`, partToString(Not(Field("field_name").Eq(ValueInt(1)))))
+	assert.Equal(t, `NOT (field_name_1 OR field_name_2)`, partToString(Not(Field("field_name_1").Or(Field("field_name_2")))))
+}
+
+func TestPart_NullSafeEq(t *testing.T) {
+	assert.Equal(t, `field_name <=> NULL`, partToString(Field("field_name").NullSafeEq(Null())))
+}
+
+func TestPrecedence(t *testing.T) {
+	a, b, c := Field("a"), Field("b"), Field("c")
+	assert.Equal(t, `(a + b) * c`, partToString(a.Add(b).Mul(c)))
+	assert.Equal(t, `a + b * c`, partToString(a.Add(b.Mul(c))))
+	assert.Equal(t, `a + b + c`, partToString(a.Add(b).Add(c)))
+	assert.Equal(t, `a + b + c`, partToString(a.Add(b.Add(c))))
+	assert.Equal(t, `a - (b - c)`, partToString(a.Sub(b.Sub(c))))
+	assert.Equal(t, `a - b - c`, partToString(a.Sub(b).Sub(c)))
+	assert.Equal(t, `a * b ^ c`, partToString(a.Mul(b.BitXor(c))))
+	assert.Equal(t, `(a OR b) AND c`, partToString(a.Or(b).And(c)))
+	assert.Equal(t, `a OR b AND c`, partToString(a.Or(b.And(c))))
+	assert.Equal(t, `a = 1 AND b = 2 OR c = 3`, partToString(a.Eq(ValueInt(1)).And(b.Eq(ValueInt(2))).Or(c.Eq(ValueInt(3)))))
+	assert.Equal(t, `(a OR b) IS TRUE`, partToString(a.Or(b).Is(True())))
+	assert.Equal(t, `a + 1 IN (1, 2)`, partToString(a.Add(ValueInt(1)).In([]Part{ValueInt(1), ValueInt(2)})))
+	assert.Equal(t, `a BETWEEN b - 1 AND (b AND c)`, partToString(a.Between(b.Sub(ValueInt(1)), b.And(c))))
+	assert.Equal(t, `(a & b) = 0`, partToString(Cond(a.BitAnd(b)).Eq(ValueInt(0))))
+	assert.Equal(t, `a & b = 0`, partToString(a.BitAnd(b).Eq(ValueInt(0))))
+	assert.Equal(t, `a = (b = c)`, partToString(a.Eq(b.Eq(c))))
+}
+
 func TestPart_Is(t *testing.T) {
 	assert.Equal(t, `field_name IS NULL`, partToString(Field("field_name").Is(Null())))
 }
@@ -139,6 +235,14 @@ func TestAverage(t *testing.T) {
 	assert.Equal(t, `CAST(AVG(field_name) AS DECIMAL(7,2))`, partToString(Average(Field("field_name"))))
 }
 
+func TestAvg(t *testing.T) {
+	assert.Equal(t, `AVG(field_name)`, partToString(Avg(Field("field_name"))))
+}
+
+func TestAverageDecimal(t *testing.T) {
+	assert.Equal(t, `CAST(AVG(field_name) AS DECIMAL(18,4))`, partToString(AverageDecimal(Field("field_name"), 18, 4)))
+}
+
 func TestToBase64(t *testing.T) {
 	assert.Equal(t, `TO_BASE64(field_name)`, partToString(ToBase64(Field("field_name"))))
 }
@@ -168,6 +272,30 @@ func TestCase(t *testing.T) {
 	assert.Equal(t, `CASE WHEN field_name = 'string' THEN 1 ELSE 0 END`, partToString(Case(Field("field_name").Eq(ValueString("string")), ValueInt(1), ValueInt(0))))
 }
 
+func TestCaseBuilder(t *testing.T) {
+	cb := NewCase().
+		When(Field("amount").Lt(ValueInt(10)), ValueString("small")).
+		When(Field("amount").Lt(ValueInt(100)), ValueString("medium"))
+	assert.Equal(t, `CASE WHEN amount < 10 THEN 'small' WHEN amount < 100 THEN 'medium' END`, partToString(cb.Part()))
+	cb.Else(ValueString("large"))
+	assert.Equal(t, `CASE WHEN amount < 10 THEN 'small' WHEN amount < 100 THEN 'medium' ELSE 'large' END`, partToString(cb.Part()))
+	_, _, err := NewCase().TryBuild()
+	assert.ErrorIs(t, err, ErrCaseNoWhen)
+	_, _, err = NewQueryFrom(Table("t")).Select(NewCase().Else(ValueInt(1)).Part()).TryBuild()
+	assert.ErrorIs(t, err, ErrCaseNoWhen)
+	assert.Panics(t, func() { NewCase().Build() })
+}
+
+func TestCaseBuilder_Value(t *testing.T) {
+	s, v := NewCaseValue(Field("status")).
+		When(ValueInt(0), ParamString("pending")).
+		When(ValueInt(1), ParamString("paid")).
+		Else(ParamString("unknown")).
+		Build()
+	assert.Equal(t, `CASE status WHEN 0 THEN ? WHEN 1 THEN ? ELSE ? END`, s)
+	assert.Equal(t, []interface{}{"pending", "paid", "unknown"}, v)
+}
+
 func TestExists(t *testing.T) {
 	assert.Equal(t, `EXISTS(SELECT * FROM table)`, partToString(Exists(NewQuery().From(Table("table")).Select(All()))))
 }
@@ -5,19 +5,11 @@ import (
 )
 
 type builder interface {
-	Build() (string, []interface{})
-}
-
-type partQuery struct {
-	s      string
-	params []interface{}
-}
-
-func (pq partQuery) Build() (string, []interface{}) {
-	return pq.s, pq.params
+	BuildTo(w *Writer)
 }
 
 type Query struct {
+	modifiers    SelectModifier
 	from         Part
 	selectParts  []Part
 	joinParts    []Part
@@ -26,6 +18,7 @@ type Query struct {
 	orderByParts []Part
 	withParts    []Part
 	limit        int
+	unscoped     bool
 }
 
 func NewQueryFrom(table Part) *Query {
@@ -47,23 +40,68 @@ func Union(lhs, rhs *Query) Part {
 	return Part{parts{partByte('('), lhs, partByte(')'), partString(" UNION "), partByte('('), rhs, partByte(')')}}
 }
 
+type SelectModifier int
+
+const (
+	SelectDistinct SelectModifier = 1 << iota
+	SelectHighPriority
+	SelectStraightJoin
+	SelectSmallResult
+	SelectBigResult
+	SelectBufferResult
+	SelectNoCache
+	SelectCalcFoundRows
+)
+
+// selectModifiers lists the modifiers in the order MariaDB expects them after
+// SELECT, whatever order they were set in.
+var selectModifiers = []struct {
+	m SelectModifier
+	s string
+}{
+	{SelectDistinct, "DISTINCT "},
+	{SelectHighPriority, "HIGH_PRIORITY "},
+	{SelectStraightJoin, "STRAIGHT_JOIN "},
+	{SelectSmallResult, "SQL_SMALL_RESULT "},
+	{SelectBigResult, "SQL_BIG_RESULT "},
+	{SelectBufferResult, "SQL_BUFFER_RESULT "},
+	{SelectNoCache, "SQL_NO_CACHE "},
+	{SelectCalcFoundRows, "SQL_CALC_FOUND_ROWS "},
+}
+
+func (q *Query) Modifiers(v ...SelectModifier) *Query {
+	for _, m := range v {
+		q.modifiers |= m
+	}
+	return q
+}
+
+func (q *Query) Distinct() *Query {
+	return q.Modifiers(SelectDistinct)
+}
+
 func (q *Query) Select(v ...Part) *Query {
 	q.selectParts = append(q.selectParts, v...)
 	return q
 }
 
 func (q *Query) LeftJoin(table Part, cond Part) *Query {
-	q.joinParts = append(q.joinParts, Part{parts{partString("LEFT JOIN "), table, partString(" ON "), cond}})
+	q.joinParts = append(q.joinParts, Part{partJoin{"LEFT JOIN ", table, cond}})
 	return q
 }
 
 func (q *Query) InnerJoin(table Part, cond Part) *Query {
-	q.joinParts = append(q.joinParts, Part{parts{partString("INNER JOIN "), table, partString(" ON "), cond}})
+	q.joinParts = append(q.joinParts, Part{partJoin{"INNER JOIN ", table, cond}})
 	return q
 }
 
+// Where adds conditions joined with AND. Zero parts are ignored.
 func (q *Query) Where(v ...Part) *Query {
-	q.whereParts = append(q.whereParts, v...)
+	for _, p := range v {
+		if !p.IsZero() {
+			q.whereParts = append(q.whereParts, p)
+		}
+	}
 	return q
 }
 
@@ -104,67 +142,96 @@ func (q *Query) WithRecursive(part Part, name string) *Query {
 	return q
 }
 
+// Part embeds the query as a subquery. The query is copied, so it can keep
+// being modified without affecting the part.
 func (q *Query) Part() Part {
-	s, ps := q.Build()
-	return Part{parts{partByte('('), partQuery{s, ps}, partByte(')')}}
+	c := *q
+	return Part{parts{partByte('('), &c, partByte(')')}}
 }
 
-func (q *Query) Build() (string, []interface{}) {
-	parts := parts{}
-	if len(q.withParts) > 0 {
-		parts = append(parts, partString("WITH "))
-		for i, v := range q.withParts {
-			if i != 0 {
-				parts = append(parts, partString(", "))
-			}
-			parts = append(parts, v)
+func writeList(w *Writer, vs []Part, sep string) {
+	for i, v := range vs {
+		if i != 0 {
+			w.WriteString(sep)
 		}
-		parts = append(parts, partByte(' '))
+		v.BuildTo(w)
 	}
+}
 
-	parts = append(parts, partString("SELECT "))
-	for i, v := range q.selectParts {
+func writeWhere(w *Writer, vs []Part) {
+	if len(vs) == 0 {
+		return
+	}
+	w.WriteString(" WHERE ")
+	for i, v := range vs {
 		if i != 0 {
-			parts = append(parts, partString(", "))
+			w.WriteString(" AND ")
+		}
+		if precedenceOf(v) < precAnd {
+			w.WriteByte('(')
+			v.BuildTo(w)
+			w.WriteByte(')')
+		} else {
+			v.BuildTo(w)
 		}
-		parts = append(parts, v)
 	}
-	parts = append(parts, partString(" FROM "))
-	parts = append(parts, q.from)
-	if len(q.joinParts) != 0 {
-		for _, v := range q.joinParts {
-			parts = append(parts, partByte(' '), v)
+}
+
+func (q *Query) BuildTo(w *Writer) {
+	if len(q.withParts) > 0 {
+		w.WriteString("WITH ")
+		writeList(w, q.withParts, ", ")
+		w.WriteByte(' ')
+	}
+
+	w.WriteString("SELECT ")
+	for _, v := range selectModifiers {
+		if q.modifiers&v.m != 0 {
+			w.WriteString(v.s)
 		}
 	}
-	if len(q.whereParts) != 0 {
-		parts = append(parts, partString(" WHERE "))
-		for i, v := range q.whereParts {
-			if i != 0 {
-				parts = append(parts, partString(" AND "))
-			}
-			parts = append(parts, v)
+	writeList(w, q.selectParts, ", ")
+	w.WriteString(" FROM ")
+	writeTableRef(w, q.from)
+	for _, v := range q.joinParts {
+		w.WriteByte(' ')
+		j, ok := v.builder.(partJoin)
+		if !ok {
+			v.BuildTo(w)
+			continue
+		}
+		if !q.unscoped {
+			j = j.withFilters()
 		}
+		j.cond = And(j.cond, tenantFilter(w, j.table))
+		j.BuildTo(w)
 	}
+	where := q.whereParts[:len(q.whereParts):len(q.whereParts)]
+	if !q.unscoped {
+		where = append(where, tableFilters(q.from)...)
+	}
+	if c := tenantFilter(w, q.from); !c.IsZero() {
+		where = append(where, c)
+	}
+	writeWhere(w, where)
 	if len(q.groupByParts) != 0 {
-		parts = append(parts, partString(" GROUP BY "))
-		for i, v := range q.groupByParts {
-			if i != 0 {
-				parts = append(parts, partString(", "))
-			}
-			parts = append(parts, v)
-		}
+		w.WriteString(" GROUP BY ")
+		writeList(w, q.groupByParts, ", ")
 	}
 	if len(q.orderByParts) != 0 {
-		parts = append(parts, partString(" ORDER BY "))
-		for i, v := range q.orderByParts {
-			if i != 0 {
-				parts = append(parts, partString(", "))
-			}
-			parts = append(parts, v)
-		}
+		w.WriteString(" ORDER BY ")
+		writeList(w, q.orderByParts, ", ")
 	}
 	if q.limit != 0 {
-		parts = append(parts, partString(" LIMIT "+strconv.Itoa(q.limit)))
+		w.WriteString(" LIMIT ")
+		w.buf = strconv.AppendInt(w.buf, int64(q.limit), 10)
 	}
-	return parts.Build()
+}
+
+func (q *Query) Build() (string, []interface{}) {
+	return build(q)
+}
+
+func (q *Query) TryBuild() (string, []interface{}, error) {
+	return tryBuild(q)
 }
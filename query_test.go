@@ -17,6 +17,20 @@ func TestQuery_Select(t *testing.T) {
 	assert.Equal(t, []string{`field_name_1 AS field_alias_1`, `COUNT(*)`, "field_name_3"}, partsToStrings(q.selectParts))
 }
 
+func TestQuery_Distinct(t *testing.T) {
+	q := NewQueryFrom(Table("table_name")).Select(Field("field_name_1"), Field("field_name_2")).Distinct()
+	s, _ := q.Build()
+	assert.Equal(t, `SELECT DISTINCT field_name_1, field_name_2 FROM table_name`, s)
+}
+
+func TestQuery_Modifiers(t *testing.T) {
+	q := NewQueryFrom(Table("table_name")).Select(All())
+	q.Modifiers(SelectCalcFoundRows, SelectNoCache)
+	q.Modifiers(SelectStraightJoin, SelectHighPriority).Distinct()
+	s, _ := q.Build()
+	assert.Equal(t, `SELECT DISTINCT HIGH_PRIORITY STRAIGHT_JOIN SQL_NO_CACHE SQL_CALC_FOUND_ROWS * FROM table_name`, s)
+}
+
 func TestQuery_InnerJoin(t *testing.T) {
 	q := NewQueryFrom(Table("table_name"))
 	q.InnerJoin(Table("table_name_2"), Field("table_name.id").Eq(Field("table_name_2.eid")))
@@ -31,6 +45,13 @@ func TestQuery_Where(t *testing.T) {
 	assert.Equal(t, []string{"field_name_1 = 123", `field_name_2 = 'string'`, "field_name_3 IS TRUE"}, partsToStrings(q.whereParts))
 }
 
+func TestQuery_WherePrecedence(t *testing.T) {
+	q := NewQueryFrom(Table("table_name")).Select(All())
+	q.Where(Field("field_name_1").Eq(ValueInt(1)).Or(Field("field_name_2").Eq(ValueInt(2))), Field("field_name_3").Is(True()))
+	s, _ := q.Build()
+	assert.Equal(t, `SELECT * FROM table_name WHERE (field_name_1 = 1 OR field_name_2 = 2) AND field_name_3 IS TRUE`, s)
+}
+
 func TestQuery_GroupBy(t *testing.T) {
 	q := NewQueryFrom(Table("table_name"))
 	q.GroupBy(Field("field_name_1"))
@@ -0,0 +1,58 @@
+package query_builder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RowScanner is satisfied by *sql.Rows and *Rows.
+type RowScanner interface {
+	Columns() ([]string, error)
+	Scan(dest ...interface{}) error
+}
+
+// ScanStruct scans the current row of rows into the struct dest points to,
+// matching columns to fields as BindStruct does. It is meant for rows
+// returned by RETURNING, to get back generated ids and defaults.
+func ScanStruct(rows RowScanner, dest interface{}) error {
+	if rv := reflect.ValueOf(dest); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected a pointer to a struct, got %T", dest)
+	}
+	rv, err := structValue(dest)
+	if err != nil {
+		return err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fields := structFields(rv.Type())
+	ptrs := make([]interface{}, len(columns))
+	for i, c := range columns {
+		for _, f := range fields {
+			if f.name == c {
+				ptrs[i] = fieldByIndex(rv, f.index).Addr().Interface()
+				break
+			}
+		}
+		if ptrs[i] == nil {
+			return fmt.Errorf("no field of %s for column %q", rv.Type(), c)
+		}
+	}
+	return rows.Scan(ptrs...)
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, allocating the nil embedded
+// structs on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ScanAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type scanUser struct {
+	*ScanAudit
+	ID   int64 `db:"id"`
+	Name string
+}
+
+func TestScanStruct(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	ts := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	f.rows = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"id", "created_at"}, [][]driver.Value{{int64(42), ts}}, nil
+	}
+	e := NewExecutor(db)
+	vb := NewValueBuilder()
+	vb.Append(ParamString("bob"))
+	u := scanUser{Name: "bob"}
+	err := e.QueryRowContext(ctx, NewInsert(Table("users")).Columns(Field("name")).Values(vb).Returning(Field("id"), Field("created_at"))).ScanStruct(&u)
+	assert.NoError(t, err)
+	assert.Equal(t, scanUser{&ScanAudit{ts}, 42, "bob"}, u)
+	assert.Equal(t, "INSERT INTO users (name) VALUES (?) RETURNING id, created_at", f.queries[0].query)
+
+	f.rows = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"id", "email"}, [][]driver.Value{{int64(42), "x"}}, nil
+	}
+	err = e.QueryRowContext(ctx, NewQueryFrom(Table("users")).Select(Field("id"), Field("email"))).ScanStruct(&u)
+	assert.EqualError(t, err, `no field of query_builder.scanUser for column "email"`)
+	assert.EqualError(t, ScanStruct(nil, u), "expected a pointer to a struct, got query_builder.scanUser")
+	assert.EqualError(t, ScanStruct(nil, (*scanUser)(nil)), "expected a pointer to a struct, got *query_builder.scanUser")
+	var n int
+	assert.EqualError(t, ScanStruct(nil, &n), "expected a struct, got *int")
+
+	f.rows = func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"id"}, [][]driver.Value{{int64(2)}}, nil
+	}
+	var s shadowUser
+	assert.NoError(t, e.QueryRowContext(ctx, NewQueryFrom(Table("users")).Select(Field("id"))).ScanStruct(&s))
+	assert.Equal(t, shadowUser{ID: 2}, s)
+}
@@ -0,0 +1,132 @@
+package query_builder
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Scope is a reusable set of changes to a query, usually conditions.
+type Scope func(*Query) *Query
+
+func (q *Query) Scopes(v ...Scope) *Query {
+	for _, s := range v {
+		q = s(q)
+	}
+	return q
+}
+
+type partTable struct {
+	name  string
+	alias string
+}
+
+// BuildTo fails the build for tenant-scoped tables: they must be used where
+// their tenant condition can be added, see writeTableRef.
+func (t partTable) BuildTo(w *Writer) {
+	if _, ok := tenantColumn(t.name); ok {
+		w.Fail(fmt.Errorf("%w: %s", ErrTenantTablePosition, t.name))
+	}
+	t.write(w)
+}
+
+func (t partTable) write(w *Writer) {
+	w.WriteString(t.name)
+	if t.alias != "" {
+		w.WriteString(" AS " + t.alias)
+	}
+}
+
+// writeTableRef writes the target of FROM, a join, INSERT or DELETE, whose
+// tenant condition, if any, is added by the caller. Tenant-scoped tables
+// given otherwise than as a Table fail the build.
+func writeTableRef(w *Writer, p Part) {
+	if t, ok := p.builder.(partTable); ok {
+		t.write(w)
+		return
+	}
+	start := w.Len()
+	p.BuildTo(w)
+	checkRawTable(w, w.buf[start:])
+}
+
+// ref is how the columns of the table are referred to in the query.
+func (t partTable) ref() string {
+	if t.alias != "" {
+		return t.alias
+	}
+	return t.name
+}
+
+// Filter returns the condition to apply to a table, ref being its alias or
+// name in the query.
+type Filter func(ref string) Part
+
+// filters holds the filters by table, and the tenant column of the tables
+// registered with RegisterTenantTable.
+var filters = struct {
+	sync.RWMutex
+	m       map[string][]Filter
+	tenants map[string]string
+}{m: map[string][]Filter{}, tenants: map[string]string{}}
+
+// RegisterFilter applies f to every query using table, in FROM or in a join,
+// unless the query is Unscoped. Filters of the FROM table go to WHERE, those
+// of joined tables to their ON condition so that outer joins keep their
+// meaning.
+func RegisterFilter(table string, f Filter) {
+	filters.Lock()
+	defer filters.Unlock()
+	filters.m[table] = append(filters.m[table], f)
+}
+
+func UnregisterFilters(table string) {
+	filters.Lock()
+	defer filters.Unlock()
+	delete(filters.m, table)
+}
+
+// tableFilters returns the conditions of the filters registered for p, when p
+// is a Table.
+func tableFilters(p Part) []Part {
+	t, ok := p.builder.(partTable)
+	if !ok {
+		return nil
+	}
+	filters.RLock()
+	fs := filters.m[t.name]
+	filters.RUnlock()
+	var ps []Part
+	for _, f := range fs {
+		if c := f(t.ref()); !c.IsZero() {
+			ps = append(ps, c)
+		}
+	}
+	return ps
+}
+
+// Unscoped disables the registered filters for the query, not for its
+// subqueries.
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	return q
+}
+
+type partJoin struct {
+	kind  string
+	table Part
+	cond  Part
+}
+
+func (j partJoin) BuildTo(w *Writer) {
+	w.WriteString(j.kind)
+	writeTableRef(w, j.table)
+	w.WriteString(" ON ")
+	j.cond.BuildTo(w)
+}
+
+func (j partJoin) withFilters() partJoin {
+	for _, f := range tableFilters(j.table) {
+		j.cond = And(j.cond, f)
+	}
+	return j
+}
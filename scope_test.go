@@ -0,0 +1,53 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Scopes(t *testing.T) {
+	active := func(q *Query) *Query {
+		return q.Where(Field("active").Is(True()))
+	}
+	paginate := func(n int) Scope {
+		return func(q *Query) *Query {
+			return q.OrderBy(Field("id"), OrderDirectionAsc).Limit(n)
+		}
+	}
+	s, _ := NewQueryFrom(Table("users")).Select(All()).Scopes(active, paginate(20)).Build()
+	assert.Equal(t, "SELECT * FROM users WHERE active IS TRUE ORDER BY id ASC LIMIT 20", s)
+}
+
+func TestRegisterFilter(t *testing.T) {
+	notDeleted := func(ref string) Part {
+		return Field(ref + ".deleted_at").Is(Null())
+	}
+	RegisterFilter("users", notDeleted)
+	RegisterFilter("orders", notDeleted)
+	RegisterFilter("orders", func(ref string) Part { return Part{} })
+	t.Cleanup(func() {
+		UnregisterFilters("users")
+		UnregisterFilters("orders")
+	})
+
+	q := NewQueryFrom(Table("users").As("u")).
+		Select(All()).
+		LeftJoin(Table("orders").As("o"), Field("o.user_id").Eq(Field("u.id")).Or(Field("o.guest").Is(True()))).
+		InnerJoin(Table("items"), Field("items.order_id").Eq(Field("o.id"))).
+		Where(Field("u.id").In([]Part{ParamInt(1)}))
+	assert.Equal(t, "SELECT * FROM users AS u "+
+		"LEFT JOIN orders AS o ON (o.user_id = u.id OR o.guest IS TRUE) AND o.deleted_at IS NULL "+
+		"INNER JOIN items ON items.order_id = o.id "+
+		"WHERE u.id IN (?) AND u.deleted_at IS NULL", builderToString(q))
+	assert.Len(t, q.whereParts, 1)
+
+	assert.Equal(t, "SELECT id FROM items WHERE EXISTS(SELECT * FROM orders WHERE orders.id = items.order_id AND orders.deleted_at IS NULL)",
+		builderToString(NewQueryFrom(Table("items")).Select(Field("id")).
+			Where(Exists(NewQueryFrom(Table("orders")).Select(All()).Where(Field("orders.id").Eq(Field("items.order_id")))))))
+
+	assert.Equal(t, "SELECT * FROM users AS u LEFT JOIN orders AS o ON o.user_id = u.id", builderToString(NewQueryFrom(Table("users").As("u")).
+		Select(All()).
+		LeftJoin(Table("orders").As("o"), Field("o.user_id").Eq(Field("u.id"))).
+		Unscoped()))
+}
@@ -0,0 +1,168 @@
+package query_builder
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+var ErrStmtCacheClosed = errors.New("statement cache is closed")
+
+type StmtCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// StmtCache prepares each distinct SQL string once and reuses the statement
+// for later calls, keeping at most size statements around. Since the builders
+// render the same SQL for the same query shape, a built query and its params
+// can be passed straight to it.
+type StmtCache struct {
+	db     *sql.DB
+	size   int
+	mu     sync.Mutex
+	lru    *list.List
+	stmts  map[string]*list.Element
+	stats  StmtCacheStats
+	closed bool
+}
+
+func NewStmtCache(db *sql.DB, size int) *StmtCache {
+	if size <= 0 {
+		panic("statement cache size must be positive")
+	}
+	return &StmtCache{
+		db:    db,
+		size:  size,
+		lru:   list.New(),
+		stmts: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrStmtCacheClosed
+	}
+	if e, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.refs++
+		c.stats.Hits++
+		c.mu.Unlock()
+		return cs, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Prepare outside of the lock so a slow round trip doesn't hold up
+	// cached statements.
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		stmt.Close()
+		return nil, ErrStmtCacheClosed
+	}
+	if e, ok := c.stmts[query]; ok {
+		// Prepared concurrently by another caller.
+		stmt.Close()
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.refs++
+		return cs, nil
+	}
+	cs := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.stmts[query] = c.lru.PushFront(cs)
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back())
+		c.stats.Evictions++
+	}
+	return cs, nil
+}
+
+// evict drops e from the cache. Its statement is closed once the last caller
+// using it releases it.
+func (c *StmtCache) evict(e *list.Element) {
+	cs := c.lru.Remove(e).(*cachedStmt)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		cs.stmt.Close()
+	}
+}
+
+func (c *StmtCache) release(cs *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs.refs--
+	if cs.evicted && cs.refs == 0 {
+		cs.stmt.Close()
+	}
+}
+
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cs)
+	return cs.stmt.ExecContext(ctx, args...)
+}
+
+// QueryContext runs a cached statement. The returned rows stay valid even if
+// the statement gets evicted before they are read.
+func (c *StmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cs)
+	return cs.stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext runs a cached statement. Errors, including
+// ErrStmtCacheClosed, are reported when scanning the row.
+func (c *StmtCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	rows, err := c.QueryContext(ctx, query, args...)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{rows: &Rows{Rows: rows, ev: &Event{}}}
+}
+
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	return stats
+}
+
+// Close closes the cached statements, those in use once released. The
+// underlying *sql.DB is left open.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for c.lru.Len() != 0 {
+		c.evict(c.lru.Back())
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtCache(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	c := NewStmtCache(db, 2)
+
+	for _, id := range []int{1, 2, 3} {
+		s, args := NewQueryFrom(Table("t")).Select(All()).Where(Field("id").Eq(ParamInt(id))).Build()
+		rows, err := c.QueryContext(ctx, s, args...)
+		assert.NoError(t, err)
+		assert.NoError(t, rows.Close())
+	}
+	_, err := c.ExecContext(ctx, "DELETE FROM t WHERE id = ?", 1)
+	assert.NoError(t, err)
+	_, err = c.ExecContext(ctx, "DELETE FROM t WHERE id = ?", 2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"SELECT * FROM t WHERE id = ?", "DELETE FROM t WHERE id = ?"}, f.prepares)
+	assert.Equal(t, StmtCacheStats{Hits: 3, Misses: 2, Size: 2}, c.Stats())
+	assert.Equal(t, []driver.Value{int64(3)}, f.queries[2].args)
+	assert.Equal(t, []driver.Value{int64(2)}, f.execs[1].args)
+}
+
+func TestStmtCache_Eviction(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	c := NewStmtCache(db, 2)
+
+	for _, q := range []string{"SELECT 1", "SELECT 2", "SELECT 1", "SELECT 3", "SELECT 2"} {
+		var v interface{}
+		assert.NoError(t, c.QueryRowContext(ctx, q).Scan(&v))
+	}
+	assert.Equal(t, []string{"SELECT 1", "SELECT 2", "SELECT 3", "SELECT 2"}, f.prepares)
+	assert.Equal(t, []string{"SELECT 2", "SELECT 1"}, f.closes)
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}, c.Stats())
+}
+
+func TestStmtCache_EvictionInUse(t *testing.T) {
+	db, f := openFakeDB(t)
+	db.SetMaxOpenConns(2)
+	ctx := context.Background()
+	c := NewStmtCache(db, 1)
+
+	cs, err := c.acquire(ctx, "SELECT 1")
+	assert.NoError(t, err)
+	_, err = c.ExecContext(ctx, "SELECT 2")
+	assert.NoError(t, err)
+	assert.Empty(t, f.closes)
+	_, err = cs.stmt.ExecContext(ctx)
+	assert.NoError(t, err)
+	c.release(cs)
+	assert.Equal(t, []string{"SELECT 1"}, f.closes)
+}
+
+func TestStmtCache_Close(t *testing.T) {
+	db, f := openFakeDB(t)
+	ctx := context.Background()
+	c := NewStmtCache(db, 4)
+
+	_, err := c.ExecContext(ctx, "SELECT 1")
+	assert.NoError(t, err)
+	_, err = c.ExecContext(ctx, "SELECT 2")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+	assert.ElementsMatch(t, []string{"SELECT 1", "SELECT 2"}, f.closes)
+	_, err = c.ExecContext(ctx, "SELECT 1")
+	assert.ErrorIs(t, err, ErrStmtCacheClosed)
+	_, err = c.QueryContext(ctx, "SELECT 1")
+	assert.ErrorIs(t, err, ErrStmtCacheClosed)
+	var v int
+	assert.ErrorIs(t, c.QueryRowContext(ctx, "SELECT 1").Scan(&v), ErrStmtCacheClosed)
+	assert.Len(t, f.prepares, 2)
+	assert.Equal(t, 0, c.Stats().Size)
+	assert.Panics(t, func() { NewStmtCache(db, 0) })
+}
@@ -0,0 +1,155 @@
+package query_builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNoTenant            = errors.New("no tenant in context for tenant-scoped table")
+	ErrTenantTablePosition = errors.New("tenant-scoped table used where its tenant condition can't be added")
+	ErrTenantConflict      = errors.New("tenant given both by the context and as a named param")
+	ErrReservedParam       = errors.New("named param reserved for the tenant")
+	ErrTenantInsert        = errors.New("insert into a tenant-scoped table must list its columns but not the tenant column")
+)
+
+// TenantParam names the param the tenant conditions get when the statement
+// is bound or compiled rather than built with a context. NamedParam refuses
+// it.
+const TenantParam = "tenant"
+
+type tenantKey struct{}
+
+func WithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	v := ctx.Value(tenantKey{})
+	return v, v != nil
+}
+
+// RegisterTenantTable scopes table to tenants: every reference to it, in FROM,
+// joins, subqueries, CTEs and DELETE, gets the condition column = tenant,
+// where the tenant comes from the context given to BuildContext. INSERT and
+// REPLACE set column to the tenant in every row, so they must list their
+// columns, without column. Bind and
+// Compile render the tenant as the named param TenantParam instead.
+//
+// Unlike filters, this can't be disabled: building a statement using the
+// table without a tenant fails with ErrNoTenant, and Build always panics then.
+// The table must be given as a Table, possibly aliased once, to FROM, a join,
+// DELETE or INSERT; anywhere else, such as in a List or as a raw string, the
+// build fails with ErrTenantTablePosition.
+//
+// Table names are matched without their schema and backticks, ignoring case.
+func RegisterTenantTable(table, column string) {
+	filters.Lock()
+	defer filters.Unlock()
+	filters.tenants[tenantTableName(table)] = column
+}
+
+func UnregisterTenantTable(table string) {
+	filters.Lock()
+	defer filters.Unlock()
+	delete(filters.tenants, tenantTableName(table))
+}
+
+func tenantTableName(table string) string {
+	table = strings.ReplaceAll(table, "`", "")
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		table = table[i+1:]
+	}
+	return strings.ToLower(table)
+}
+
+func tenantColumn(table string) (string, bool) {
+	filters.RLock()
+	defer filters.RUnlock()
+	column, ok := filters.tenants[tenantTableName(table)]
+	return column, ok
+}
+
+// checkRawTable fails the build when the raw SQL s, written in the place of a
+// table, starts with the name of a tenant-scoped table.
+func checkRawTable(w *Writer, s []byte) {
+	f := strings.Fields(string(s))
+	if len(f) == 0 || strings.HasPrefix(f[0], "(") {
+		return
+	}
+	if _, ok := tenantColumn(f[0]); ok {
+		w.Fail(fmt.Errorf("%w: %s", ErrTenantTablePosition, f[0]))
+	}
+}
+
+// tenantValue returns the tenant column of t and the value it must hold, when
+// t is tenant-scoped. The value is zero when the build failed for lack of a
+// tenant.
+func tenantValue(w *Writer, t partTable) (string, Part) {
+	column, ok := tenantColumn(t.name)
+	if !ok {
+		return "", Part{}
+	}
+	switch {
+	case w.tenant != nil:
+		return column, Param(w.tenant)
+	case w.lookup != nil || w.unresolved:
+		return column, Part{partNamedParam(TenantParam)}
+	}
+	w.Fail(fmt.Errorf("%w %s", ErrNoTenant, t.name))
+	return column, Part{}
+}
+
+// tenantFilter returns the tenant condition of p, when p is a tenant-scoped
+// Table.
+func tenantFilter(w *Writer, p Part) Part {
+	t, ok := p.builder.(partTable)
+	if !ok {
+		return Part{}
+	}
+	column, v := tenantValue(w, t)
+	if v.IsZero() {
+		return Part{}
+	}
+	return Field(t.ref() + "." + column).Eq(v)
+}
+
+// buildUnresolved builds b leaving its named params and tenant as
+// placeholders, for renderings not meant to be run as is.
+func buildUnresolved(b builder) (string, []interface{}, error) {
+	w := Writer{unresolved: true}
+	b.BuildTo(&w)
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	return w.String(), w.args, nil
+}
+
+func buildContext(ctx context.Context, b builder) (string, []interface{}, error) {
+	w := Writer{}
+	w.tenant, _ = TenantFromContext(ctx)
+	b.BuildTo(&w)
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	return w.String(), w.args, nil
+}
+
+// BuildContext builds the query with the tenant of ctx, if any.
+func (q *Query) BuildContext(ctx context.Context) (string, []interface{}, error) {
+	return buildContext(ctx, q)
+}
+
+func (iq *InsertQuery) BuildContext(ctx context.Context) (string, []interface{}, error) {
+	return buildContext(ctx, iq)
+}
+
+func (dq *DeleteQuery) BuildContext(ctx context.Context) (string, []interface{}, error) {
+	return buildContext(ctx, dq)
+}
+
+func (p Part) BuildContext(ctx context.Context) (string, []interface{}, error) {
+	return buildContext(ctx, p)
+}
@@ -0,0 +1,161 @@
+package query_builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterTenantTable(t *testing.T) {
+	RegisterTenantTable("users", "tenant_id")
+	RegisterTenantTable("orders", "tenant_id")
+	RegisterFilter("orders", func(ref string) Part { return Field(ref + ".deleted_at").Is(Null()) })
+	t.Cleanup(func() {
+		UnregisterTenantTable("users")
+		UnregisterTenantTable("orders")
+		UnregisterFilters("orders")
+	})
+	ctx := WithTenant(context.Background(), 7)
+
+	recent := NewQueryFrom(Table("orders")).Select(Field("user_id")).Where(Field("created_at").Gt(ParamString("2020-01-01")))
+	q := NewQuery().
+		With(recent.Part(), Field("recent")).
+		From(Table("users").As("u")).
+		Select(All()).
+		LeftJoin(Table("orders").As("o"), Field("o.user_id").Eq(Field("u.id"))).
+		Where(Exists(NewQueryFrom(Table("recent")).Select(All()).Where(Field("recent.user_id").Eq(Field("u.id"))))).
+		Unscoped()
+	s, args, err := q.BuildContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH recent AS ((SELECT user_id FROM orders WHERE created_at > ? AND orders.deleted_at IS NULL AND orders.tenant_id = ?)) "+
+		"SELECT * FROM users AS u LEFT JOIN orders AS o ON o.user_id = u.id AND o.tenant_id = ? "+
+		"WHERE EXISTS(SELECT * FROM recent WHERE recent.user_id = u.id) AND u.tenant_id = ?", s)
+	assert.Equal(t, []interface{}{"2020-01-01", 7, 7, 7}, args)
+
+	_, _, err = q.BuildContext(context.Background())
+	assert.ErrorIs(t, err, ErrNoTenant)
+	assert.EqualError(t, err, "no tenant in context for tenant-scoped table orders")
+	_, _, err = q.TryBuild()
+	assert.ErrorIs(t, err, ErrNoTenant)
+	assert.Panics(t, func() { q.Build() })
+	assert.Equal(t, "SELECT * FROM items", builderToString(NewQueryFrom(Table("items")).Select(All())))
+
+	s, args, err = NewDelete(Table("users")).Where(Field("id").Eq(ParamInt(3))).BuildContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? AND users.tenant_id = ?", s)
+	assert.Equal(t, []interface{}{3, 7}, args)
+	_, _, err = NewDelete(Table("users")).TryBuild()
+	assert.ErrorIs(t, err, ErrNoTenant)
+}
+
+func TestExecutor_Tenant(t *testing.T) {
+	RegisterTenantTable("users", "tenant_id")
+	t.Cleanup(func() { UnregisterTenantTable("users") })
+	db, f := openFakeDB(t)
+	e := NewExecutor(db)
+
+	_, err := e.ExecContext(context.Background(), NewDelete(Table("users")))
+	assert.ErrorIs(t, err, ErrNoTenant)
+	_, err = e.ExecContext(WithTenant(context.Background(), "acme"), NewDelete(Table("users")))
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE users.tenant_id = ?", f.execs[0].query)
+}
+
+func TestTenantTablePosition(t *testing.T) {
+	RegisterTenantTable("users", "tenant_id")
+	t.Cleanup(func() { UnregisterTenantTable("users") })
+	ctx := WithTenant(context.Background(), 7)
+
+	_, _, err := NewQueryFrom(List(Table("users"), Table("x"))).Select(All()).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTenantTablePosition)
+	assert.EqualError(t, err, "tenant-scoped table used where its tenant condition can't be added: users")
+	_, _, err = NewQueryFrom(Table("users").As("u").As("v")).Select(All()).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTenantTablePosition)
+	_, _, err = NewQueryFrom(Table("x")).Select(All()).LeftJoin(List(Table("users")), Field("x")).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTenantTablePosition)
+	for _, p := range []Part{Field("users"), Alias("Users u"), Field("app.users"), FieldNp("app", "`users`")} {
+		_, _, err = NewQueryFrom(p).Select(All()).BuildContext(ctx)
+		assert.ErrorIs(t, err, ErrTenantTablePosition, p)
+		_, _, err = NewDelete(p).BuildContext(ctx)
+		assert.ErrorIs(t, err, ErrTenantTablePosition, p)
+	}
+	_, _, err = NewQueryFrom(Table("users u")).Select(All()).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTableName)
+
+	s, args, err := NewQueryFrom(Table("app.Users")).Select(All()).BuildContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM app.Users WHERE app.Users.tenant_id = ?", s)
+	assert.Equal(t, []interface{}{7}, args)
+	s, _, err = NewQueryFrom(Table("`users`").As("u")).Select(All()).BuildContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` AS u WHERE u.tenant_id = ?", s)
+	_, _, err = NewQueryFrom(NewQueryFrom(Table("x")).Select(All()).Part().As("users")).Select(All()).BuildContext(ctx)
+	assert.NoError(t, err)
+}
+
+func TestTenantInsert(t *testing.T) {
+	RegisterTenantTable("users", "tenant_id")
+	t.Cleanup(func() { UnregisterTenantTable("users") })
+	ctx := WithTenant(context.Background(), 7)
+	vb := NewValueBuilder()
+	vb.Append(ParamString("a"))
+	vb.Append(ParamString("b"))
+
+	iq := NewInsert(Table("users")).Columns(Field("name")).Values(vb)
+	s, args, err := iq.BuildContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name, tenant_id) VALUES (?, ?), (?, ?)", s)
+	assert.Equal(t, []interface{}{"a", 7, "b", 7}, args)
+	_, _, err = iq.TryBuild()
+	assert.ErrorIs(t, err, ErrNoTenant)
+	assert.Panics(t, func() { iq.Build() })
+
+	stmts, err := NewBatchInsert(iq).MaxRows(1).StatementsContext(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 2)
+	s, args = stmts[1].Build()
+	assert.Equal(t, "INSERT INTO users (name, tenant_id) VALUES (?, ?)", s)
+	assert.Equal(t, []interface{}{"b", 7}, args)
+	_, err = NewBatchInsert(iq).Statements()
+	assert.ErrorIs(t, err, ErrNoTenant)
+
+	_, _, err = NewReplace(Table("users")).Values(vb).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTenantInsert)
+	_, _, err = NewInsert(Table("users")).Columns(Field("name"), Field("Tenant_ID")).Values(vb).BuildContext(ctx)
+	assert.ErrorIs(t, err, ErrTenantInsert)
+}
+
+func TestTenantNamedParam(t *testing.T) {
+	RegisterTenantTable("users", "tenant_id")
+	t.Cleanup(func() { UnregisterTenantTable("users") })
+	q := NewQueryFrom(Table("users")).Select(All()).Where(Field("id").Eq(NamedParam("id")))
+
+	cq, err := q.Compile()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? AND users.tenant_id = ?", cq.SQL())
+	args, err := cq.Exec(map[string]interface{}{"id": 1, TenantParam: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 7}, args)
+	_, err = cq.Exec(map[string]interface{}{"id": 1})
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+	args, err = cq.ExecContext(WithTenant(context.Background(), 8), map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 8}, args)
+	_, err = cq.ExecContext(WithTenant(context.Background(), 8), map[string]interface{}{"id": 1, "x": 2})
+	assert.ErrorIs(t, err, ErrUnusedNamedParam)
+	_, err = cq.ExecContext(WithTenant(context.Background(), 8), map[string]interface{}{"id": 1, TenantParam: 99})
+	assert.ErrorIs(t, err, ErrTenantConflict)
+	_, err = NewQueryFrom(Table("items")).Select(All()).Where(Field("owner").Eq(NamedParam(TenantParam))).Compile()
+	assert.ErrorIs(t, err, ErrReservedParam)
+
+	s, args, err := q.Bind(map[string]interface{}{"id": 1, TenantParam: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? AND users.tenant_id = ?", s)
+	assert.Equal(t, []interface{}{1, 7}, args)
+
+	assert.NotPanics(t, func() { q.Fingerprint() })
+	assert.Equal(t, FingerprintSQL(cq.SQL()), q.Fingerprint())
+	s, _ = q.BuildPretty()
+	assert.Contains(t, s, "users.tenant_id = ?")
+}
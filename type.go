@@ -0,0 +1,170 @@
+package query_builder
+
+import "strconv"
+
+type SQLType struct {
+	s string
+}
+
+func (t SQLType) String() string {
+	return t.s
+}
+
+// Unsigned is meant for the integer column types, the CAST target is
+// TypeUnsigned.
+func (t SQLType) Unsigned() SQLType {
+	return SQLType{t.s + " UNSIGNED"}
+}
+
+var (
+	tSigned   = SQLType{"SIGNED"}
+	tUnsigned = SQLType{"UNSIGNED"}
+	tDate     = SQLType{"DATE"}
+	tDateTime = SQLType{"DATETIME"}
+	tTime     = SQLType{"TIME"}
+	tDouble   = SQLType{"DOUBLE"}
+	tJson     = SQLType{"JSON"}
+	tBool     = SQLType{"BOOLEAN"}
+	tTinyInt  = SQLType{"TINYINT"}
+	tSmallInt = SQLType{"SMALLINT"}
+	tInt      = SQLType{"INT"}
+	tBigInt   = SQLType{"BIGINT"}
+	tFloat    = SQLType{"FLOAT"}
+	tText     = SQLType{"TEXT"}
+	tLongText = SQLType{"LONGTEXT"}
+	tBlob     = SQLType{"BLOB"}
+	tLongBlob = SQLType{"LONGBLOB"}
+)
+
+func TypeSigned() SQLType {
+	return tSigned
+}
+
+func TypeUnsigned() SQLType {
+	return tUnsigned
+}
+
+func TypeDecimal(precision, scale int) SQLType {
+	return SQLType{"DECIMAL(" + strconv.Itoa(precision) + "," + strconv.Itoa(scale) + ")"}
+}
+
+func sizedType(name string, n int) SQLType {
+	if n <= 0 {
+		return SQLType{name}
+	}
+	return SQLType{name + "(" + strconv.Itoa(n) + ")"}
+}
+
+// TypeChar renders CHAR(n), or a plain CHAR when n is not positive.
+func TypeChar(n int) SQLType {
+	return sizedType("CHAR", n)
+}
+
+func TypeBinary(n int) SQLType {
+	return sizedType("BINARY", n)
+}
+
+func TypeDate() SQLType {
+	return tDate
+}
+
+func TypeDateTime() SQLType {
+	return tDateTime
+}
+
+func TypeTime() SQLType {
+	return tTime
+}
+
+func TypeDouble() SQLType {
+	return tDouble
+}
+
+func TypeJson() SQLType {
+	return tJson
+}
+
+func TypeBool() SQLType {
+	return tBool
+}
+
+func TypeTinyInt() SQLType {
+	return tTinyInt
+}
+
+func TypeSmallInt() SQLType {
+	return tSmallInt
+}
+
+func TypeInt() SQLType {
+	return tInt
+}
+
+func TypeBigInt() SQLType {
+	return tBigInt
+}
+
+func TypeFloat() SQLType {
+	return tFloat
+}
+
+func TypeVarchar(n int) SQLType {
+	return sizedType("VARCHAR", n)
+}
+
+func TypeVarbinary(n int) SQLType {
+	return sizedType("VARBINARY", n)
+}
+
+func TypeText() SQLType {
+	return tText
+}
+
+func TypeLongText() SQLType {
+	return tLongText
+}
+
+func TypeBlob() SQLType {
+	return tBlob
+}
+
+func TypeLongBlob() SQLType {
+	return tLongBlob
+}
+
+// TypeTimestamp renders TIMESTAMP(fsp), or a plain TIMESTAMP when fsp is not
+// positive.
+func TypeTimestamp(fsp int) SQLType {
+	return sizedType("TIMESTAMP", fsp)
+}
+
+func TypeEnum(values ...string) SQLType {
+	s := "ENUM("
+	for i, v := range values {
+		if i != 0 {
+			s += ", "
+		}
+		s += quoteString(v)
+	}
+	return SQLType{s + ")"}
+}
+
+// Cast renders CAST(v AS t). MariaDB can't cast to JSON; JSON_COMPACT is used
+// instead, which flags its result as JSON the same way.
+func Cast(v Part, t SQLType) Part {
+	if t == tJson {
+		return Func("JSON_COMPACT", v)
+	}
+	return Part{parts{partString("CAST("), v, partString(" AS " + t.s + ")")}}
+}
+
+func Convert(v Part, t SQLType) Part {
+	if t == tJson {
+		return Func("JSON_COMPACT", v)
+	}
+	return Part{parts{partString("CONVERT("), v, partString(", " + t.s + ")")}}
+}
+
+func ConvertUsing(v Part, charset string) Part {
+	return Part{parts{partString("CONVERT("), v, partString(" USING " + charset + ")")}}
+}
@@ -0,0 +1,52 @@
+package query_builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLType(t *testing.T) {
+	assert.Equal(t, "SIGNED", TypeSigned().String())
+	assert.Equal(t, "UNSIGNED", TypeUnsigned().String())
+	assert.Equal(t, "DECIMAL(12,2)", TypeDecimal(12, 2).String())
+	assert.Equal(t, "CHAR(10)", TypeChar(10).String())
+	assert.Equal(t, "CHAR", TypeChar(0).String())
+	assert.Equal(t, "BINARY(16)", TypeBinary(16).String())
+	assert.Equal(t, "DATE", TypeDate().String())
+	assert.Equal(t, "DATETIME", TypeDateTime().String())
+	assert.Equal(t, "TIME", TypeTime().String())
+	assert.Equal(t, "DOUBLE", TypeDouble().String())
+	assert.Equal(t, "JSON", TypeJson().String())
+}
+
+func TestSQLType_Columns(t *testing.T) {
+	assert.Equal(t, "BOOLEAN", TypeBool().String())
+	assert.Equal(t, "TINYINT", TypeTinyInt().String())
+	assert.Equal(t, "SMALLINT", TypeSmallInt().String())
+	assert.Equal(t, "INT", TypeInt().String())
+	assert.Equal(t, "BIGINT UNSIGNED", TypeBigInt().Unsigned().String())
+	assert.Equal(t, "FLOAT", TypeFloat().String())
+	assert.Equal(t, "VARCHAR(255)", TypeVarchar(255).String())
+	assert.Equal(t, "VARBINARY(16)", TypeVarbinary(16).String())
+	assert.Equal(t, "TEXT", TypeText().String())
+	assert.Equal(t, "LONGTEXT", TypeLongText().String())
+	assert.Equal(t, "BLOB", TypeBlob().String())
+	assert.Equal(t, "LONGBLOB", TypeLongBlob().String())
+	assert.Equal(t, "TIMESTAMP", TypeTimestamp(0).String())
+	assert.Equal(t, "TIMESTAMP(6)", TypeTimestamp(6).String())
+	assert.Equal(t, `ENUM('a', 'it\'s')`, TypeEnum("a", "it's").String())
+}
+
+func TestCast(t *testing.T) {
+	assert.Equal(t, `CAST(field_name AS UNSIGNED)`, partToString(Cast(Field("field_name"), TypeUnsigned())))
+	assert.Equal(t, `CAST(? AS DATETIME)`, partToString(Cast(ParamString("2001-02-03"), TypeDateTime())))
+	assert.Equal(t, `CAST(field_name AS CHAR(10))`, partToString(Cast(Field("field_name"), TypeChar(10))))
+	assert.Equal(t, `JSON_COMPACT(field_name)`, partToString(Cast(Field("field_name"), TypeJson())))
+}
+
+func TestConvert(t *testing.T) {
+	assert.Equal(t, `CONVERT(field_name, DECIMAL(10,3))`, partToString(Convert(Field("field_name"), TypeDecimal(10, 3))))
+	assert.Equal(t, `JSON_COMPACT(field_name)`, partToString(Convert(Field("field_name"), TypeJson())))
+	assert.Equal(t, `CONVERT(field_name USING utf8mb4)`, partToString(ConvertUsing(Field("field_name"), "utf8mb4")))
+}
@@ -0,0 +1,103 @@
+package query_builder
+
+import "io"
+
+// Writer accumulates the SQL and params of a statement while it is built.
+// Every builder writes into the same Writer in a single pass; a Writer can be
+// Reset and reused to build without allocating once its buffers have grown.
+type Writer struct {
+	buf   []byte
+	args  []interface{}
+	err   error
+	named []namedPosition
+	// lookup resolves named params while binding, nil otherwise.
+	lookup func(name string) (interface{}, bool)
+	tenant interface{}
+	// unresolved leaves named params, the TenantParam one of tenant conditions
+	// included, as placeholders to resolve later: for Compile and renderings
+	// not meant to be run.
+	unresolved bool
+}
+
+func (w *Writer) WriteString(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+func (w *Writer) WriteByte(c byte) error {
+	w.buf = append(w.buf, c)
+	return nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// WriteParam writes a placeholder bound to v.
+func (w *Writer) WriteParam(v interface{}) {
+	w.buf = append(w.buf, '?')
+	w.args = append(w.args, v)
+}
+
+// Fail records err as the error of the build. Only the first error is kept.
+func (w *Writer) Fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *Writer) Err() error {
+	return w.err
+}
+
+func (w *Writer) Len() int {
+	return len(w.buf)
+}
+
+// Bytes returns the SQL written so far. Like Args, it is only valid until the
+// Writer is Reset.
+func (w *Writer) Bytes() []byte {
+	return w.buf
+}
+
+func (w *Writer) String() string {
+	return string(w.buf)
+}
+
+func (w *Writer) Args() []interface{} {
+	return w.args
+}
+
+func (w *Writer) WriteTo(dst io.Writer) (int64, error) {
+	n, err := dst.Write(w.buf)
+	return int64(n), err
+}
+
+func (w *Writer) Reset() {
+	w.buf = w.buf[:0]
+	for i := range w.args {
+		w.args[i] = nil
+	}
+	w.args = w.args[:0]
+	w.err = nil
+	w.named = w.named[:0]
+}
+
+func tryBuild(b builder) (string, []interface{}, error) {
+	var w Writer
+	b.BuildTo(&w)
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	return w.String(), w.args, nil
+}
+
+// build is used by the Build methods, which panic if the build fails; TryBuild
+// returns the error instead.
+func build(b builder) (string, []interface{}) {
+	s, args, err := tryBuild(b)
+	if err != nil {
+		panic(err)
+	}
+	return s, args
+}
@@ -0,0 +1,78 @@
+package query_builder
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	var w Writer
+	q := NewQueryFrom(Table("t")).Select(All()).Where(Field("id").Eq(ParamInt(1))).Limit(10)
+	q.BuildTo(&w)
+	assert.Equal(t, "SELECT * FROM t WHERE id = ? LIMIT 10", w.String())
+	assert.Equal(t, []interface{}{1}, w.Args())
+
+	w.Reset()
+	Field("a").In([]Part{ParamString("x"), ParamString("y")}).BuildTo(&w)
+	assert.Equal(t, "a IN (?, ?)", string(w.Bytes()))
+	assert.Equal(t, []interface{}{"x", "y"}, w.Args())
+
+	var sb strings.Builder
+	n, err := w.WriteTo(&sb)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+	assert.Equal(t, "a IN (?, ?)", sb.String())
+}
+
+func TestQuery_PartCopy(t *testing.T) {
+	q := NewQueryFrom(Table("t")).Select(Field("id")).Where(Field("a").Eq(ParamInt(1)))
+	p := q.Part()
+	q.Where(Field("b").Eq(ParamInt(2))).Limit(1)
+	s, args := NewQueryFrom(Table("u")).Select(All()).Where(Field("id").Eq(p)).Build()
+	assert.Equal(t, "SELECT * FROM u WHERE id = (SELECT id FROM t WHERE a = ?)", s)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func wideSelect() *Query {
+	q := NewQueryFrom(Table("t").As("t"))
+	for i := 0; i < 100; i++ {
+		f := Field("t.f" + strconv.Itoa(i))
+		q.Select(f).Where(f.Eq(ParamInt(i)).Or(f.IsNot(Null())))
+	}
+	return q.InnerJoin(Table("u").As("u"), Field("u.id").Eq(Field("t.u_id"))).OrderBy(Field("t.f0"), OrderDirectionAsc)
+}
+
+func BenchmarkBuild_WideSelect(b *testing.B) {
+	q := wideSelect()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Build()
+	}
+}
+
+func BenchmarkBuildTo_WideSelect(b *testing.B) {
+	q := wideSelect()
+	var w Writer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		q.BuildTo(&w)
+	}
+}
+
+func BenchmarkBuild_Values10k(b *testing.B) {
+	vb := NewValueBuilder()
+	for i := 0; i < 10000; i++ {
+		vb.Append(ParamInt(i), ParamString("name"), Now())
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vb.Build()
+	}
+}